@@ -1,44 +1,325 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 )
 
+const (
+	ProviderTypeServer1 = "server1"
+	ProviderTypeServer2 = "server2"
+	ProviderTypeJSON    = "json"
+)
+
+const (
+	AuthTypeNone   = ""
+	AuthTypeBearer = "bearer"
+	AuthTypeBasic  = "basic"
+	AuthTypeHeader = "header"
+)
+
+const (
+	ParsingLenient = "lenient"
+	ParsingStrict  = "strict"
+)
+
 type JSONServerConfig struct {
 	Name string
 	Port string
 }
 
+type AuthConfig struct {
+	Type     string `mapstructure:"type"`
+	Token    string `mapstructure:"token"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	Header   string `mapstructure:"header"`
+}
+
+type ProviderConfig struct {
+	Name    string             `mapstructure:"name"`
+	Type    string             `mapstructure:"type"`
+	BaseURL string             `mapstructure:"base_url"`
+	Path    string             `mapstructure:"path"`
+	Timeout time.Duration      `mapstructure:"timeout"`
+	Enabled *bool              `mapstructure:"enabled"`
+	Auth    AuthConfig         `mapstructure:"auth"`
+	Mapping FieldMappingConfig `mapstructure:"mapping"`
+	Parsing string             `mapstructure:"parsing"`
+}
+
+type FieldMappingConfig struct {
+	Records       string `mapstructure:"records"`
+	Reference     string `mapstructure:"reference"`
+	Segments      string `mapstructure:"segments"`
+	FlightNumber  string `mapstructure:"flight_number"`
+	From          string `mapstructure:"from"`
+	To            string `mapstructure:"to"`
+	DepartureTime string `mapstructure:"departure_time"`
+	ArrivalTime   string `mapstructure:"arrival_time"`
+	Price         string `mapstructure:"price"`
+	Currency      string `mapstructure:"currency"`
+}
+
+type RetryConfig struct {
+	MaxAttempts          int
+	BaseDelay            time.Duration
+	MaxDelay             time.Duration
+	Jitter               float64
+	RetryableStatusCodes []int
+}
+
+type CircuitBreakerConfig struct {
+	FailureThreshold int
+	CoolDown         time.Duration
+}
+
+type CacheConfig struct {
+	TTL                  time.Duration
+	StaleWhileRevalidate time.Duration
+	StaleIfError         time.Duration
+}
+
+type SearchDefaultsConfig struct {
+	SortBy     string
+	Order      string
+	Validation string
+}
+
 type AppConfig struct {
-	JServer1 JSONServerConfig
-	JServer2 JSONServerConfig
+	Providers         []ProviderConfig
+	RepositoryTimeout time.Duration
+	SearchDefaults    SearchDefaultsConfig
+	Retry             RetryConfig
+	CircuitBreaker    CircuitBreakerConfig
+	Cache             CacheConfig
+	AdminToken        string
 }
 
 func Load() (*AppConfig, error) {
 	viper.AutomaticEnv()
 
+	if configFile := viper.GetString("CONFIG_FILE"); configFile != "" {
+		viper.SetConfigFile(configFile)
+	} else {
+		viper.SetConfigName("config")
+		viper.AddConfigPath(".")
+	}
+
+	if err := viper.ReadInConfig(); err != nil {
+		var notFoundErr viper.ConfigFileNotFoundError
+
+		if !errors.As(err, &notFoundErr) {
+			return nil, fmt.Errorf("read config file: %w", err)
+		}
+	}
+
+	viper.SetDefault("REPOSITORY_TIMEOUT", "5s")
+	viper.SetDefault("DEFAULT_SORT", "price")
+	viper.SetDefault("DEFAULT_ORDER", "asc")
+	viper.SetDefault("VALIDATION_MODE", "strict")
+	viper.SetDefault("RETRY_MAX_ATTEMPTS", 3)
+	viper.SetDefault("RETRY_BASE_DELAY", "100ms")
+	viper.SetDefault("RETRY_MAX_DELAY", "2s")
+	viper.SetDefault("RETRY_JITTER", 0.5)
+	viper.SetDefault("RETRY_STATUS_CODES", []int{429, 500, 502, 503, 504})
+	viper.SetDefault("CIRCUIT_FAILURE_THRESHOLD", 5)
+	viper.SetDefault("CIRCUIT_COOL_DOWN", "30s")
+	viper.SetDefault("CACHE_TTL", "60s")
+	viper.SetDefault("CACHE_STALE_WHILE_REVALIDATE", "30s")
+	viper.SetDefault("CACHE_STALE_IF_ERROR", "10m")
+
+	return decode()
+}
+
+func decode() (*AppConfig, error) {
 	config := &AppConfig{
-		JServer1: JSONServerConfig{
-			Name: viper.GetString("JSERVER1_NAME"),
-			Port: viper.GetString("JSERVER1_PORT"),
+		RepositoryTimeout: viper.GetDuration("REPOSITORY_TIMEOUT"),
+		SearchDefaults: SearchDefaultsConfig{
+			SortBy:     viper.GetString("DEFAULT_SORT"),
+			Order:      viper.GetString("DEFAULT_ORDER"),
+			Validation: viper.GetString("VALIDATION_MODE"),
+		},
+		Retry: RetryConfig{
+			MaxAttempts: viper.GetInt("RETRY_MAX_ATTEMPTS"),
+			BaseDelay:   viper.GetDuration("RETRY_BASE_DELAY"),
+			MaxDelay:    viper.GetDuration("RETRY_MAX_DELAY"),
+			Jitter:      viper.GetFloat64("RETRY_JITTER"),
+		},
+		CircuitBreaker: CircuitBreakerConfig{
+			FailureThreshold: viper.GetInt("CIRCUIT_FAILURE_THRESHOLD"),
+			CoolDown:         viper.GetDuration("CIRCUIT_COOL_DOWN"),
 		},
-		JServer2: JSONServerConfig{
-			Name: viper.GetString("JSERVER2_NAME"),
-			Port: viper.GetString("JSERVER2_PORT"),
+		Cache: CacheConfig{
+			TTL:                  viper.GetDuration("CACHE_TTL"),
+			StaleWhileRevalidate: viper.GetDuration("CACHE_STALE_WHILE_REVALIDATE"),
+			StaleIfError:         viper.GetDuration("CACHE_STALE_IF_ERROR"),
 		},
+		AdminToken: viper.GetString("ADMIN_TOKEN"),
+	}
+
+	retryableStatusCodes, err := parseStatusCodes(viper.GetStringSlice("RETRY_STATUS_CODES"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	config.Retry.RetryableStatusCodes = retryableStatusCodes
+
+	if err := viper.UnmarshalKey("providers", &config.Providers); err != nil {
+		return nil, fmt.Errorf("decode providers: %w", err)
+	}
+
+	if len(config.Providers) == 0 {
+		providers, err := legacyProviders()
+
+		if err != nil {
+			return nil, err
+		}
+
+		config.Providers = providers
+	}
+
+	if err := validateProviders(config.Providers); err != nil {
+		return nil, err
 	}
 
-	if config.JServer1.Name == "" || config.JServer1.Port == "" {
+	return config, nil
+}
+
+func Watch(onReload func(*AppConfig, error)) bool {
+	if viper.ConfigFileUsed() == "" {
+		return false
+	}
+
+	viper.OnConfigChange(func(event fsnotify.Event) {
+		onReload(decode())
+	})
+
+	viper.WatchConfig()
+
+	return true
+}
+
+func FileUsed() string {
+	return viper.ConfigFileUsed()
+}
+
+func legacyProviders() ([]ProviderConfig, error) {
+	jServer1 := JSONServerConfig{
+		Name: viper.GetString("JSERVER1_NAME"),
+		Port: viper.GetString("JSERVER1_PORT"),
+	}
+
+	jServer2 := JSONServerConfig{
+		Name: viper.GetString("JSERVER2_NAME"),
+		Port: viper.GetString("JSERVER2_PORT"),
+	}
+
+	if jServer1.Name == "" || jServer1.Port == "" {
 		return nil, fmt.Errorf("missing JSERVER1_* envs")
 	}
 
-	if config.JServer2.Name == "" || config.JServer2.Port == "" {
+	if jServer2.Name == "" || jServer2.Port == "" {
 		return nil, fmt.Errorf("missing JSERVER2_* envs")
 	}
 
-	return config, nil
+	return []ProviderConfig{
+		{Name: jServer1.Name, Type: ProviderTypeServer1, BaseURL: jServer1.BaseURL()},
+		{Name: jServer2.Name, Type: ProviderTypeServer2, BaseURL: jServer2.BaseURL()},
+	}, nil
+}
+
+// parseStatusCodes accepts a list, from the config file, or a comma-separated string
+// such as "500,503", from the environment.
+func parseStatusCodes(values []string) ([]int, error) {
+	var statusCodes []int
+
+	for _, value := range values {
+		for _, field := range strings.Split(value, ",") {
+			field = strings.TrimSpace(field)
+
+			if field == "" {
+				continue
+			}
+
+			statusCode, err := strconv.Atoi(field)
+
+			if err != nil || statusCode < 100 || statusCode > 599 {
+				return nil, fmt.Errorf("RETRY_STATUS_CODES: invalid status code %q", field)
+			}
+
+			statusCodes = append(statusCodes, statusCode)
+		}
+	}
+
+	return statusCodes, nil
+}
+
+func validateProviders(providers []ProviderConfig) error {
+	providerNames := make(map[string]bool, len(providers))
+
+	for index := range providers {
+		provider := &providers[index]
+
+		if provider.Name == "" {
+			return fmt.Errorf("provider #%d: missing name", index+1)
+		}
+
+		if providerNames[provider.Name] {
+			return fmt.Errorf("provider %s: duplicate name", provider.Name)
+		}
+
+		providerNames[provider.Name] = true
+
+		if provider.BaseURL == "" {
+			return fmt.Errorf("provider %s: missing base_url", provider.Name)
+		}
+
+		provider.BaseURL = strings.TrimSuffix(provider.BaseURL, "/")
+
+		switch provider.Type {
+		case ProviderTypeServer1, ProviderTypeServer2, ProviderTypeJSON:
+		case "":
+			provider.Type = ProviderTypeJSON
+		default:
+			return fmt.Errorf("provider %s: unknown type %q", provider.Name, provider.Type)
+		}
+
+		switch provider.Parsing {
+		case ParsingLenient, ParsingStrict:
+		case "":
+			provider.Parsing = ParsingLenient
+		default:
+			return fmt.Errorf("provider %s: unknown parsing mode %q", provider.Name, provider.Parsing)
+		}
+
+		switch provider.Auth.Type {
+		case AuthTypeNone, AuthTypeBearer, AuthTypeBasic:
+		case AuthTypeHeader:
+			if provider.Auth.Header == "" {
+				return fmt.Errorf("provider %s: auth type header requires a header name", provider.Name)
+			}
+		default:
+			return fmt.Errorf("provider %s: unknown auth type %q", provider.Name, provider.Auth.Type)
+		}
+
+		provider.Auth.Token = os.ExpandEnv(provider.Auth.Token)
+		provider.Auth.Password = os.ExpandEnv(provider.Auth.Password)
+	}
+
+	return nil
+}
+
+func (providerConfig ProviderConfig) IsEnabled() bool {
+	return providerConfig.Enabled == nil || *providerConfig.Enabled
 }
 
 func (j JSONServerConfig) BaseURL() string {
@@ -2,6 +2,19 @@ package domain
 
 import "time"
 
+type Segment struct {
+	FlightNumber  string    `json:"flightNumber"`
+	From          string    `json:"from"`
+	To            string    `json:"to"`
+	DepartureTime time.Time `json:"departureTime"`
+	ArrivalTime   time.Time `json:"arrivalTime"`
+}
+
+type Layover struct {
+	Airport         string `json:"airport"`
+	DurationMinutes int    `json:"durationMinutes"`
+}
+
 type Flight struct {
 	Reference         string    `json:"reference"`
 	FlightNumber      string    `json:"flightNumber"`
@@ -12,8 +25,35 @@ type Flight struct {
 	Price             float64   `json:"price"`
 	Currency          string    `json:"currency"`
 	TravelTimeMinutes int       `json:"travelTimeMinutes"`
+	Segments          []Segment `json:"segments"`
+	Stops             int       `json:"stops"`
+	Layovers          []Layover `json:"layovers"`
+	// VirtualInterline marks itineraries chaining flights sold separately, listed in Tickets.
+	VirtualInterline bool     `json:"virtualInterline,omitempty"`
+	Tickets          []string `json:"tickets,omitempty"`
 }
 
 func (flight Flight) Duration() time.Duration {
 	return flight.ArrivalTime.Sub(flight.DepartureTime)
 }
+
+func (flight Flight) StopCount() int {
+	if len(flight.Segments) <= 1 {
+		return 0
+	}
+
+	return len(flight.Segments) - 1
+}
+
+func (flight Flight) ConnectionLayovers() []Layover {
+	layovers := make([]Layover, 0, flight.StopCount())
+
+	for i := 1; i < len(flight.Segments); i++ {
+		layovers = append(layovers, Layover{
+			Airport:         flight.Segments[i].From,
+			DurationMinutes: int(flight.Segments[i].DepartureTime.Sub(flight.Segments[i-1].ArrivalTime).Minutes()),
+		})
+	}
+
+	return layovers
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Itinerary is a sequence of flights booked together, priced as the sum of its flights.
+type Itinerary struct {
+	Reference         string    `json:"reference"`
+	From              string    `json:"from"`
+	To                string    `json:"to"`
+	DepartureTime     time.Time `json:"departureTime"`
+	ArrivalTime       time.Time `json:"arrivalTime"`
+	Price             float64   `json:"price"`
+	Currency          string    `json:"currency"`
+	TravelTimeMinutes int       `json:"travelTimeMinutes"`
+	StayMinutes       int       `json:"stayMinutes"`
+	Flights           []Flight  `json:"flights"`
+}
+
+// NewItinerary chains flights that are already in travel order. The travel time only
+// counts the flights themselves, the time spent between them is the stay.
+func NewItinerary(flights ...Flight) Itinerary {
+	firstFlight := flights[0]
+	lastFlight := flights[len(flights)-1]
+
+	itinerary := Itinerary{
+		From:          firstFlight.From,
+		To:            lastFlight.To,
+		DepartureTime: firstFlight.DepartureTime,
+		ArrivalTime:   lastFlight.ArrivalTime,
+		Currency:      firstFlight.Currency,
+		Flights:       flights,
+	}
+
+	references := make([]string, 0, len(flights))
+	var travelTime time.Duration
+
+	for index, flight := range flights {
+		references = append(references, flight.Reference)
+		itinerary.Price += flight.Price
+		travelTime += flight.Duration()
+
+		if index > 0 {
+			itinerary.StayMinutes += int(flight.DepartureTime.Sub(flights[index-1].ArrivalTime).Minutes())
+		}
+	}
+
+	itinerary.Reference = strings.Join(references, "+")
+	itinerary.TravelTimeMinutes = int(travelTime.Minutes())
+
+	return itinerary
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/problem"
+)
+
+const (
+	ReloadStatusOK    = "ok"
+	ReloadStatusError = "error"
+)
+
+type ReloadStatus struct {
+	ConfigFile       string     `json:"config_file,omitempty"`
+	WatchEnabled     bool       `json:"watch_enabled"`
+	Providers        []string   `json:"providers"`
+	ReloadCount      int        `json:"reload_count"`
+	FailureCount     int        `json:"failure_count"`
+	LastReloadAt     *time.Time `json:"last_reload_at,omitempty"`
+	LastReloadStatus string     `json:"last_reload_status,omitempty"`
+	LastError        string     `json:"last_error,omitempty"`
+}
+
+// AdminHandler serves the reload status only to requests bearing its token. Without a
+// token the endpoint is disabled.
+type AdminHandler struct {
+	mutex  sync.Mutex
+	status ReloadStatus
+	token  string
+}
+
+func NewAdminHandler(configFile string, providers []string) *AdminHandler {
+	return &AdminHandler{
+		status: ReloadStatus{
+			ConfigFile:   configFile,
+			WatchEnabled: configFile != "",
+			Providers:    providers,
+		},
+	}
+}
+
+func (adminHandler *AdminHandler) SetToken(token string) {
+	adminHandler.mutex.Lock()
+	defer adminHandler.mutex.Unlock()
+
+	adminHandler.token = token
+}
+
+func (adminHandler *AdminHandler) RecordReload(providers []string, err error) {
+	adminHandler.mutex.Lock()
+	defer adminHandler.mutex.Unlock()
+
+	reloadedAt := time.Now().UTC()
+
+	adminHandler.status.ReloadCount++
+	adminHandler.status.LastReloadAt = &reloadedAt
+
+	if err != nil {
+		adminHandler.status.FailureCount++
+		adminHandler.status.LastReloadStatus = ReloadStatusError
+		adminHandler.status.LastError = err.Error()
+
+		return
+	}
+
+	adminHandler.status.Providers = providers
+	adminHandler.status.LastReloadStatus = ReloadStatusOK
+	adminHandler.status.LastError = ""
+}
+
+func (adminHandler *AdminHandler) Status() ReloadStatus {
+	adminHandler.mutex.Lock()
+	defer adminHandler.mutex.Unlock()
+
+	status := adminHandler.status
+	status.Providers = slices.Clone(status.Providers)
+
+	return status
+}
+
+func (adminHandler *AdminHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	adminHandler.mutex.Lock()
+	token := adminHandler.token
+	adminHandler.mutex.Unlock()
+
+	if token == "" {
+		problem.Write(writer, request, problem.New("", http.StatusNotFound, "no route for "+request.URL.Path))
+
+		return
+	}
+
+	bearerToken, hasBearer := strings.CutPrefix(request.Header.Get("Authorization"), "Bearer ")
+
+	if !hasBearer || subtle.ConstantTimeCompare([]byte(bearerToken), []byte(token)) != 1 {
+		writer.Header().Set("WWW-Authenticate", "Bearer")
+		problem.Write(writer, request, problem.New("", http.StatusUnauthorized, "a valid admin token is required"))
+
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+
+	writer.WriteHeader(http.StatusOK)
+
+	_ = json.NewEncoder(writer).Encode(adminHandler.Status())
+}
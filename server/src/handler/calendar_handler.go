@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Orden14/flight-aggregator/src/problem"
+)
+
+// ServeCalendar answers /flights/calendar with the cheapest price of each day of a month.
+func (flightHandler *FlightHandler) ServeCalendar(writer http.ResponseWriter, request *http.Request) {
+	calendarQuery, err := parseCalendarQuery(request.URL.Query(), *flightHandler.defaults.Load())
+
+	if err != nil {
+		writeQueryProblem(writer, request, err)
+
+		return
+	}
+
+	calendar, err := flightHandler.flightService.PriceCalendar(request.Context(), calendarQuery)
+
+	if err != nil {
+		problem.Write(writer, request, searchProblem(err))
+
+		return
+	}
+
+	if cacheStatus := cacheStatusHeader(calendar.Providers); cacheStatus != "" {
+		writer.Header().Set("Cache-Status", cacheStatus)
+	}
+
+	response := map[string]any{
+		"from":      calendarQuery.Outbound.DepartureAirport,
+		"to":        calendarQuery.Outbound.ArrivalAirport,
+		"month":     calendarQuery.Month,
+		"mode":      calendarQuery.Mode,
+		"providers": calendar.Providers,
+		"days":      calendar.Days,
+	}
+
+	if calendarQuery.RoundTrip {
+		response["pairs"] = calendar.Pairs
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+
+	json.NewEncoder(writer).Encode(response)
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/service"
+)
+
+// parseCalendarQuery takes the round-trip parameters, the month replacing the departure dates.
+func parseCalendarQuery(query url.Values, defaults SearchDefaults) (service.CalendarQuery, error) {
+	queryParser := newQueryParser(query, defaults)
+	queryParser.rejectUnsupported("is not supported by the price calendar", "departure_date", "departure_date_from", "departure_date_to", "limit", "offset", "cursor", "interline")
+	roundTripQuery, _ := queryParser.parseRoundTrip(defaults)
+	calendarQuery := service.CalendarQuery{RoundTripQuery: roundTripQuery, Month: query.Get("month")}
+
+	if _, err := time.Parse("2006-01", calendarQuery.Month); err != nil {
+		queryParser.reject("month", calendarQuery.Month, "expected a month such as 2026-01")
+	}
+
+	if roundTripQuery.Outbound.DepartureAirport == "" {
+		queryParser.reject("from", "", "is required for a price calendar, unless near is set")
+	}
+
+	if roundTripQuery.Outbound.ArrivalAirport == "" {
+		queryParser.reject("to", "", "is required for a price calendar")
+	}
+
+	if roundTrip := query.Get("round_trip"); roundTrip != "" {
+		isRoundTrip, err := strconv.ParseBool(roundTrip)
+
+		if err != nil {
+			queryParser.reject("round_trip", roundTrip, "expected true or false")
+		}
+
+		calendarQuery.RoundTrip = isRoundTrip
+	}
+
+	// One-way days are single flights, so the price bounds go back to the outbound flight.
+	if !calendarQuery.RoundTrip {
+		calendarQuery.Outbound.MinPrice, calendarQuery.Outbound.MaxPrice = roundTripQuery.MinPrice, roundTripQuery.MaxPrice
+		calendarQuery.MinPrice, calendarQuery.MaxPrice = nil, nil
+	}
+
+	return calendarQuery, queryParser.err()
+}
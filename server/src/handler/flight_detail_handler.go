@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/Orden14/flight-aggregator/src/problem"
+)
+
+// ServeDetail answers /flights/{reference}. Raw provider records are only included when
+// the raw parameter is set, as they can be large.
+func (flightHandler *FlightHandler) ServeDetail(writer http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+	queryParser := &queryParser{query: query, mode: flightHandler.defaults.Load().Validation}
+
+	fetchMode := queryParser.parseFetchMode("mode")
+	includeRaw := false
+
+	if raw := query.Get("raw"); raw != "" {
+		isRaw, err := strconv.ParseBool(raw)
+
+		if err != nil {
+			queryParser.reject("raw", raw, "expected true or false")
+		}
+
+		includeRaw = isRaw
+	}
+
+	if len(queryParser.invalidParams) > 0 {
+		problem.Write(writer, request, validationProblem(&ValidationError{InvalidParams: queryParser.invalidParams}))
+
+		return
+	}
+
+	detail, err := flightHandler.flightService.GetFlight(request.Context(), request.PathValue("reference"), fetchMode)
+
+	if err != nil {
+		problem.Write(writer, request, searchProblem(err))
+
+		return
+	}
+
+	if !includeRaw {
+		for index := range detail.Offers {
+			detail.Offers[index].Raw = nil
+		}
+	}
+
+	providers := make([]string, 0, len(detail.Offers))
+
+	for _, offer := range detail.Offers {
+		providers = append(providers, offer.Provider)
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+
+	json.NewEncoder(writer).Encode(map[string]any{
+		"flight":       detail.Flight,
+		"offered_by":   providers,
+		"offers":       detail.Offers,
+		"offers_count": len(detail.Offers),
+		"providers":    detail.Providers,
+	})
+}
@@ -2,43 +2,201 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
 
+	"github.com/Orden14/flight-aggregator/src/problem"
 	"github.com/Orden14/flight-aggregator/src/service"
+	"github.com/Orden14/flight-aggregator/src/util/pagination"
 	"github.com/Orden14/flight-aggregator/src/util/sorter"
 )
 
+type SearchDefaults struct {
+	SortBy     sorter.SortBy
+	SortOrder  sorter.Order
+	Validation ValidationMode
+}
+
 type FlightHandler struct {
 	flightService service.FlightService
+	defaults      atomic.Pointer[SearchDefaults]
 }
 
 func NewFlightHandler(flightService service.FlightService) *FlightHandler {
-	return &FlightHandler{flightService: flightService}
+	flightHandler := &FlightHandler{flightService: flightService}
+	flightHandler.SetDefaults(SearchDefaults{SortBy: sorter.SortByPrice, SortOrder: sorter.OrderAsc, Validation: ValidationStrict})
+
+	return flightHandler
+}
+
+func (flightHandler *FlightHandler) SetDefaults(defaults SearchDefaults) {
+	flightHandler.defaults.Store(&defaults)
 }
 
 func (flightHandler *FlightHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
-	query := request.URL.Query()
+	writer.Header().Add("Vary", "Accept")
+
+	outputFormat, isAcceptable := flightHandler.outputFormat(writer, request)
+
+	if !isAcceptable {
+		return
+	}
 
-	departureAirport := query.Get("from")
-	arrivalAirport := query.Get("to")
+	flightQuery, page, isValid := flightHandler.parseQuery(writer, request)
 
-	sortBy := sorter.NormalizeSortBy(query.Get("sort"))
-	sortOrder := sorter.NormalizeOrder(query.Get("order"))
+	if !isValid {
+		return
+	}
 
-	flights, err := flightHandler.flightService.GetFlights(request.Context(), departureAirport, arrivalAirport, sortBy, sortOrder)
+	result, err := flightHandler.flightService.GetFlights(request.Context(), flightQuery)
 
 	if err != nil {
-		http.Error(writer, "failed to fetch flights: "+err.Error(), http.StatusBadGateway)
+		problem.Write(writer, request, searchProblem(err))
 
 		return
 	}
 
-	writer.Header().Set("Content-Type", "application/json")
+	resultPage, err := page.paginate(result.Flights, result.Generation)
+
+	if err != nil {
+		problem.Write(writer, request, problem.New(problem.TypeStaleCursor, http.StatusConflict, err.Error()+", restart from the first page"))
+
+		return
+	}
+
+	links := page.links(request.URL, resultPage, flightQuery.SortBy, flightQuery.SortOrder, result.Generation)
+	links.setHeaders(writer.Header(), resultPage.Total)
+
+	if cacheStatus := cacheStatusHeader(result.Providers); cacheStatus != "" {
+		writer.Header().Set("Cache-Status", cacheStatus)
+	}
+
+	writer.Header().Set("Content-Type", outputContentTypes[outputFormat])
+
+	if outputFormat != OutputFormatJSON {
+		writer.Header().Set("X-Provider-Status", providerStatusHeader(result.Providers))
+	}
+
+	switch outputFormat {
+	case OutputFormatNDJSON:
+		writeNDJSON(writer, resultPage.Flights)
+	case OutputFormatCSV:
+		writeCSV(writer, resultPage.Flights)
+	default:
+		json.NewEncoder(writer).Encode(searchResponse(flightQuery, result, resultPage, links))
+	}
+}
+
+// outputFormat reads the format parameter, which wins over the Accept header, and writes
+// the problem itself when neither names a supported format.
+func (flightHandler *FlightHandler) outputFormat(writer http.ResponseWriter, request *http.Request) (OutputFormat, bool) {
+	if format := request.URL.Query().Get("format"); format != "" {
+		outputFormat, isValid := ParseOutputFormat(format)
+
+		if !isValid {
+			problem.Write(writer, request, validationProblem(&ValidationError{InvalidParams: []InvalidParam{
+				{Name: "format", Value: format, Reason: "expected json, ndjson or csv"},
+			}}))
+		}
+
+		return outputFormat, isValid
+	}
+
+	outputFormat, isAcceptable := negotiateOutputFormat(request.Header.Get("Accept"))
+
+	if !isAcceptable {
+		problem.Write(writer, request, problem.New(problem.TypeNotAcceptable, http.StatusNotAcceptable, "supported media types are application/json, application/x-ndjson and text/csv"))
+	}
+
+	return outputFormat, isAcceptable
+}
+
+func searchResponse(flightQuery service.FlightQuery, result *service.FlightSearchResult, resultPage pagination.Page, links pageLinks) map[string]any {
+	return map[string]any{
+		"flights_count": len(resultPage.Flights),
+		"total":         resultPage.Total,
+		"offset":        resultPage.Start,
+		"next":          optionalLink(links.Next),
+		"prev":          optionalLink(links.Prev),
+		"sort_by":       flightQuery.SortBy,
+		"sort_order":    flightQuery.SortOrder,
+		"mode":          flightQuery.Mode,
+		"providers":     result.Providers,
+		"items":         resultPage.Flights,
+	}
+}
+
+func optionalLink(link string) *string {
+	if link == "" {
+		return nil
+	}
+
+	return &link
+}
+
+// parseQuery writes the 400 problem itself when the query is invalid.
+func (flightHandler *FlightHandler) parseQuery(writer http.ResponseWriter, request *http.Request) (service.FlightQuery, pageRequest, bool) {
+	flightQuery, page, err := parseFlightQuery(request.URL.Query(), *flightHandler.defaults.Load())
+
+	if err == nil {
+		return flightQuery, page, true
+	}
+
+	writeQueryProblem(writer, request, err)
+
+	return flightQuery, page, false
+}
+
+func writeQueryProblem(writer http.ResponseWriter, request *http.Request, err error) {
+	var validationError *ValidationError
+
+	if errors.As(err, &validationError) {
+		problem.Write(writer, request, validationProblem(validationError))
+	} else {
+		problem.Write(writer, request, problem.New("", http.StatusBadRequest, err.Error()))
+	}
+}
+
+// providerStatusHeader carries the provider statuses that NDJSON and CSV have no room
+// for, e.g. "j-server1=ok, j-server2=error; category=timeout".
+func providerStatusHeader(providers []service.ProviderStatus) string {
+	entries := make([]string, 0, len(providers))
+
+	for _, provider := range providers {
+		entry := provider.Name + "=" + provider.Status
+
+		if provider.ErrorCategory != "" {
+			entry += "; category=" + provider.ErrorCategory
+		}
+
+		if provider.SkippedRecords > 0 {
+			entry += "; skipped=" + strconv.Itoa(provider.SkippedRecords)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return strings.Join(entries, ", ")
+}
+
+func cacheStatusHeader(providers []service.ProviderStatus) string {
+	entries := make([]string, 0, len(providers))
+
+	for _, provider := range providers {
+		switch service.CacheStatus(provider.Cache) {
+		case service.CacheStatusHit:
+			entries = append(entries, provider.Name+"; hit")
+		case service.CacheStatusMiss:
+			entries = append(entries, provider.Name+"; fwd=miss")
+		case service.CacheStatusCollapsed:
+			entries = append(entries, provider.Name+"; fwd=miss; collapsed")
+		case service.CacheStatusStale:
+			entries = append(entries, provider.Name+"; hit; ttl="+strconv.FormatInt(provider.TTLSeconds, 10))
+		}
+	}
 
-	json.NewEncoder(writer).Encode(map[string]any{
-		"flights_count": len(flights),
-		"sort_by":       sortBy,
-		"sort_order":    sortOrder,
-		"items":         flights,
-	})
+	return strings.Join(entries, ", ")
 }
@@ -0,0 +1,444 @@
+package handler
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/service"
+	"github.com/Orden14/flight-aggregator/src/util/airport"
+	"github.com/Orden14/flight-aggregator/src/util/filter"
+	"github.com/Orden14/flight-aggregator/src/util/route"
+	"github.com/Orden14/flight-aggregator/src/util/sorter"
+)
+
+const maxRadiusKm = 1000
+
+// maxQueryDuration bounds the duration parameters, well past any real layover or trip.
+const maxQueryDuration = 7 * 24 * time.Hour
+
+var (
+	airportCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+	airlineCodePattern = regexp.MustCompile(`^([A-Z0-9]{2}|[A-Z]{3})$`)
+)
+
+// queryParser collects every invalid parameter instead of stopping at the first one.
+// In lenient mode airport codes are used as given and invalid enums keep their default.
+type queryParser struct {
+	query         url.Values
+	mode          ValidationMode
+	invalidParams []InvalidParam
+}
+
+func (queryParser *queryParser) reject(parameter string, inputValue string, reason string) {
+	queryParser.invalidParams = append(queryParser.invalidParams, InvalidParam{Name: parameter, Value: inputValue, Reason: reason})
+}
+
+// rejectUnsupported rejects the parameters an endpoint does not take and drops them, so
+// the rest of the parsing does not report them a second time.
+func (queryParser *queryParser) rejectUnsupported(reason string, parameters ...string) {
+	queryParser.query = maps.Clone(queryParser.query)
+
+	for _, parameter := range parameters {
+		if inputValue := queryParser.query.Get(parameter); inputValue != "" {
+			queryParser.reject(parameter, inputValue, reason)
+			queryParser.query.Del(parameter)
+		}
+	}
+}
+
+func (queryParser *queryParser) isStrict() bool {
+	return queryParser.mode != ValidationLenient
+}
+
+// err returns every parameter rejected so far as a single error.
+func (queryParser *queryParser) err() error {
+	if len(queryParser.invalidParams) > 0 {
+		return &ValidationError{InvalidParams: queryParser.invalidParams}
+	}
+
+	return nil
+}
+
+// newQueryParser applies the validation parameter, which overrides the configured mode.
+func newQueryParser(query url.Values, defaults SearchDefaults) *queryParser {
+	queryParser := &queryParser{query: query, mode: defaults.Validation}
+
+	if validation := query.Get("validation"); validation != "" {
+		validationMode, isValid := ParseValidationMode(validation)
+
+		if !isValid {
+			queryParser.reject("validation", validation, "expected strict or lenient")
+		}
+
+		queryParser.mode = validationMode
+	}
+
+	return queryParser
+}
+
+func parseFlightQuery(query url.Values, defaults SearchDefaults) (service.FlightQuery, pageRequest, error) {
+	queryParser := newQueryParser(query, defaults)
+	flightQuery, page := queryParser.parseSearch(defaults)
+
+	return flightQuery, page, queryParser.err()
+}
+
+// parseStreamQuery rejects paging, since the stream always sends every flight.
+func parseStreamQuery(query url.Values, defaults SearchDefaults) (service.FlightQuery, error) {
+	queryParser := newQueryParser(query, defaults)
+	queryParser.rejectUnsupported("is not supported by the stream, which sends every flight", "limit", "offset", "cursor")
+	flightQuery, _ := queryParser.parseSearch(defaults)
+
+	return flightQuery, queryParser.err()
+}
+
+func (queryParser *queryParser) parseSearch(defaults SearchDefaults) (service.FlightQuery, pageRequest) {
+	query := queryParser.query
+
+	flightQuery := service.FlightQuery{
+		DepartureAirport: queryParser.parseAirport("from"),
+		ArrivalAirport:   queryParser.parseAirport("to"),
+		SortBy:           defaults.SortBy,
+		SortOrder:        defaults.SortOrder,
+		Mode:             queryParser.parseFetchMode("mode"),
+	}
+
+	queryParser.parseNear(&flightQuery)
+
+	if sortBy := query.Get("sort"); sortBy != "" {
+		parsedSortBy, isValid := sorter.ParseSortBy(sortBy)
+
+		if !isValid && queryParser.isStrict() {
+			queryParser.reject("sort", sortBy, "expected price, travel_time or departure_date")
+		}
+
+		if isValid {
+			flightQuery.SortBy = parsedSortBy
+		}
+	}
+
+	if sortOrder := query.Get("order"); sortOrder != "" {
+		parsedOrder, isValid := sorter.ParseOrder(sortOrder)
+
+		if !isValid && queryParser.isStrict() {
+			queryParser.reject("order", sortOrder, "expected asc or desc")
+		}
+
+		if isValid {
+			flightQuery.SortOrder = parsedOrder
+		}
+	}
+
+	if maxStops := query.Get("max_stops"); maxStops != "" {
+		stops, err := strconv.Atoi(maxStops)
+
+		if err != nil || stops < 0 {
+			queryParser.reject("max_stops", maxStops, "expected a non-negative integer")
+		} else {
+			flightQuery.MaxStops = &stops
+		}
+	}
+
+	if directOnly := query.Get("direct_only"); directOnly != "" {
+		isDirectOnly, err := strconv.ParseBool(directOnly)
+
+		if err != nil {
+			queryParser.reject("direct_only", directOnly, "expected true or false")
+		}
+
+		flightQuery.DirectOnly = isDirectOnly
+	}
+
+	flightQuery.MinLayover = queryParser.parseLayover("min_layover")
+	flightQuery.MaxLayover = queryParser.parseLayover("max_layover")
+
+	flightQuery.MaxDuration = queryParser.parseLayover("max_duration")
+
+	if interline := query.Get("interline"); interline != "" {
+		isInterline, err := strconv.ParseBool(interline)
+
+		if err != nil {
+			queryParser.reject("interline", interline, "expected true or false")
+		} else if isInterline && (flightQuery.DepartureAirport == "" || flightQuery.ArrivalAirport == "") {
+			queryParser.reject("interline", interline, "requires both from and to")
+		}
+
+		flightQuery.Interline = isInterline
+	}
+
+	flightQuery.MinConnection = route.DefaultMinConnection
+
+	if query.Get("min_connection") != "" {
+		flightQuery.MinConnection = queryParser.parseLayover("min_connection")
+	}
+
+	flightQuery.MaxConnection = route.DefaultMaxConnection
+
+	if query.Get("max_connection") != "" {
+		flightQuery.MaxConnection = queryParser.parseLayover("max_connection")
+	}
+
+	if queryParser.isStrict() && flightQuery.MaxConnection > 0 && flightQuery.MinConnection > flightQuery.MaxConnection {
+		queryParser.reject("min_connection", query.Get("min_connection"), "must not be above max_connection")
+	}
+
+	if queryParser.isStrict() && flightQuery.MinLayover > 0 && flightQuery.MaxLayover > 0 && flightQuery.MinLayover > flightQuery.MaxLayover {
+		queryParser.reject("min_layover", query.Get("min_layover"), "must not be above max_layover")
+	}
+
+	flightQuery.DepartureDateFrom, flightQuery.DepartureDateTo = queryParser.parseDateRange("departure_date")
+
+	flightQuery.DepartureWindow = queryParser.parseTimeWindow("departure_time_from", "departure_time_to")
+	flightQuery.ArrivalWindow = queryParser.parseTimeWindow("arrival_time_from", "arrival_time_to")
+
+	flightQuery.MinPrice = queryParser.parsePrice("min_price")
+	flightQuery.MaxPrice = queryParser.parsePrice("max_price")
+
+	if flightQuery.MinPrice != nil && flightQuery.MaxPrice != nil && *flightQuery.MinPrice > *flightQuery.MaxPrice {
+		queryParser.reject("min_price", query.Get("min_price"), fmt.Sprintf("must not be above max_price %v", *flightQuery.MaxPrice))
+	}
+
+	flightQuery.Airlines = queryParser.parseAirlines("airline")
+	flightQuery.ExcludedAirlines = queryParser.parseAirlines("exclude_airline")
+	flightQuery.FlightNumber = query.Get("flight_number")
+
+	return flightQuery, queryParser.parsePage(flightQuery.SortBy, flightQuery.SortOrder)
+}
+
+func (queryParser *queryParser) parseAirport(parameter string) string {
+	inputValue := queryParser.query.Get(parameter)
+
+	if inputValue != "" && queryParser.isStrict() && !airportCodePattern.MatchString(inputValue) {
+		queryParser.reject(parameter, inputValue, "expected a 3-letter uppercase IATA airport code such as CDG")
+	}
+
+	return inputValue
+}
+
+// parseNear reads near, an alternative to from, and the radii widening the departure
+// and arrival airports.
+func (queryParser *queryParser) parseNear(flightQuery *service.FlightQuery) {
+	query := queryParser.query
+
+	flightQuery.DepartureRadiusKm = queryParser.parseRadius("radius_km")
+	flightQuery.ArrivalRadiusKm = queryParser.parseRadius("to_radius_km")
+
+	if near := query.Get("near"); near != "" {
+		switch {
+		case flightQuery.DepartureAirport != "":
+			queryParser.reject("near", near, "cannot be combined with from")
+		case !airport.Known(near):
+			queryParser.reject("near", near, "expected an airport or city code of the airport reference")
+		case query.Get("radius_km") == "":
+			queryParser.reject("near", near, "requires radius_km")
+		}
+
+		flightQuery.DepartureAirport = near
+	}
+
+	if query.Get("radius_km") != "" && flightQuery.DepartureAirport == "" {
+		queryParser.reject("radius_km", query.Get("radius_km"), "requires from or near")
+	}
+
+	if query.Get("to_radius_km") != "" && flightQuery.ArrivalAirport == "" {
+		queryParser.reject("to_radius_km", query.Get("to_radius_km"), "requires to")
+	}
+}
+
+func (queryParser *queryParser) parseRadius(parameter string) float64 {
+	inputValue := queryParser.query.Get(parameter)
+
+	if inputValue == "" {
+		return 0
+	}
+
+	radiusKm, err := strconv.ParseFloat(inputValue, 64)
+
+	if err != nil || radiusKm <= 0 || radiusKm > maxRadiusKm {
+		queryParser.reject(parameter, inputValue, fmt.Sprintf("expected a number of kilometres above 0 and up to %d", maxRadiusKm))
+
+		return 0
+	}
+
+	return radiusKm
+}
+
+func (queryParser *queryParser) parseFetchMode(parameter string) service.FetchMode {
+	inputValue := queryParser.query.Get(parameter)
+	fetchMode, isValid := service.ParseFetchMode(inputValue)
+
+	if inputValue != "" && !isValid && queryParser.isStrict() {
+		queryParser.reject(parameter, inputValue, "expected degraded or strict")
+	}
+
+	return fetchMode
+}
+
+func (queryParser *queryParser) parsePrice(parameter string) *float64 {
+	inputValue := queryParser.query.Get(parameter)
+
+	if inputValue == "" {
+		return nil
+	}
+
+	price, err := strconv.ParseFloat(inputValue, 64)
+
+	if err != nil || price < 0 || math.IsNaN(price) || math.IsInf(price, 0) {
+		queryParser.reject(parameter, inputValue, "expected a non-negative number")
+
+		return nil
+	}
+
+	return &price
+}
+
+// parseAirlines accepts both repeated parameters and comma-separated values.
+func (queryParser *queryParser) parseAirlines(parameter string) []string {
+	var values []string
+
+	for _, inputValue := range queryParser.query[parameter] {
+		for _, value := range strings.Split(inputValue, ",") {
+			if value = strings.ToUpper(strings.TrimSpace(value)); value == "" {
+				continue
+			}
+
+			if queryParser.isStrict() && !airlineCodePattern.MatchString(value) {
+				queryParser.reject(parameter, value, "expected a 2-character IATA or 3-letter ICAO airline code")
+
+				continue
+			}
+
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// parseDateRange reads either a single date parameter or its _from and _to bounds.
+func (queryParser *queryParser) parseDateRange(parameter string) (string, string) {
+	query := queryParser.query
+	fromParameter, toParameter := parameter+"_from", parameter+"_to"
+
+	if query.Get(parameter) != "" && (query.Get(fromParameter) != "" || query.Get(toParameter) != "") {
+		queryParser.reject(parameter, query.Get(parameter), "cannot be combined with "+fromParameter+" or "+toParameter)
+
+		return "", ""
+	}
+
+	var from, to string
+
+	for _, dateParameter := range []string{parameter, fromParameter, toParameter} {
+		inputValue := query.Get(dateParameter)
+
+		if inputValue == "" {
+			continue
+		}
+
+		if _, err := time.Parse(time.DateOnly, inputValue); err != nil {
+			queryParser.reject(dateParameter, inputValue, "expected a date such as 2026-01-01")
+
+			continue
+		}
+
+		switch dateParameter {
+		case parameter:
+			from, to = inputValue, inputValue
+		case fromParameter:
+			from = inputValue
+		case toParameter:
+			to = inputValue
+		}
+	}
+
+	if from != "" && to != "" && from > to {
+		queryParser.reject(fromParameter, from, "must not be after "+toParameter+" "+to)
+	}
+
+	return from, to
+}
+
+func (queryParser *queryParser) parseTimeWindow(fromParameter string, toParameter string) *filter.TimeWindow {
+	fromValue, toValue := queryParser.query.Get(fromParameter), queryParser.query.Get(toParameter)
+
+	if fromValue == "" && toValue == "" {
+		return nil
+	}
+
+	timeWindow := &filter.TimeWindow{From: 0, To: 24*time.Hour - time.Second}
+	isValid := true
+
+	if fromValue != "" {
+		from, err := parseClock(fromValue)
+
+		if err != nil {
+			queryParser.reject(fromParameter, fromValue, "expected a local time such as 08:00")
+			isValid = false
+		}
+
+		timeWindow.From = from
+	}
+
+	if toValue != "" {
+		to, err := parseClock(toValue)
+
+		if err != nil {
+			queryParser.reject(toParameter, toValue, "expected a local time such as 08:00")
+			isValid = false
+		}
+
+		timeWindow.To = to
+	}
+
+	if !isValid {
+		return nil
+	}
+
+	return timeWindow
+}
+
+func parseClock(inputValue string) (time.Duration, error) {
+	clock, err := time.Parse("15:04", inputValue)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
+
+// parseLayover accepts a number of minutes ("90") or a Go duration ("1h30m").
+func (queryParser *queryParser) parseLayover(parameter string) time.Duration {
+	inputValue := queryParser.query.Get(parameter)
+
+	if inputValue == "" {
+		return 0
+	}
+
+	duration, err := time.ParseDuration(inputValue)
+
+	if minutes, minutesErr := strconv.Atoi(inputValue); minutesErr == nil {
+		// Capped before the conversion, which would otherwise overflow.
+		duration, err = time.Duration(min(minutes, int(maxQueryDuration/time.Minute)+1))*time.Minute, nil
+	}
+
+	if err != nil || duration < 0 {
+		queryParser.reject(parameter, inputValue, "expected minutes or a duration such as 1h30m")
+
+		return 0
+	}
+
+	if duration > maxQueryDuration {
+		queryParser.reject(parameter, inputValue, "must not exceed 7 days")
+
+		return 0
+	}
+
+	return duration
+}
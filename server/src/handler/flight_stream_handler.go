@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Orden14/flight-aggregator/src/problem"
+	"github.com/Orden14/flight-aggregator/src/service"
+	"github.com/Orden14/flight-aggregator/src/util/pagination"
+)
+
+const (
+	StreamEventProvider      = "provider"
+	StreamEventProviderError = "provider_error"
+	StreamEventSummary       = "summary"
+	StreamEventError         = "error"
+)
+
+// ServeStream sends one Server-Sent Event per provider, then a summary or error event.
+func (flightHandler *FlightHandler) ServeStream(writer http.ResponseWriter, request *http.Request) {
+	flightQuery, err := parseStreamQuery(request.URL.Query(), *flightHandler.defaults.Load())
+
+	if err != nil {
+		writeQueryProblem(writer, request, err)
+
+		return
+	}
+
+	responseController := http.NewResponseController(writer)
+
+	writer.Header().Set("Content-Type", "text/event-stream")
+	writer.Header().Set("Cache-Control", "no-cache")
+	writer.Header().Set("X-Accel-Buffering", "no")
+	writer.WriteHeader(http.StatusOK)
+
+	eventID := 0
+
+	writeEvent := func(event string, payload any) {
+		data, err := json.Marshal(payload)
+
+		if err != nil {
+			return
+		}
+
+		eventID++
+		fmt.Fprintf(writer, "id: %d\nevent: %s\ndata: %s\n\n", eventID, event, data)
+		_ = responseController.Flush()
+	}
+
+	result, err := flightHandler.flightService.StreamFlights(request.Context(), flightQuery, func(batch service.ProviderBatch) {
+		if batch.Provider.Status == service.ProviderStatusError {
+			writeEvent(StreamEventProviderError, map[string]any{"provider": batch.Provider})
+
+			return
+		}
+
+		writeEvent(StreamEventProvider, map[string]any{
+			"provider":      batch.Provider,
+			"flights_count": len(batch.Flights),
+			"items":         batch.Flights,
+		})
+	})
+
+	if err != nil {
+		searchProblem := searchProblem(err).With("request_id", problem.RequestID(request))
+		searchProblem.Instance = request.URL.Path
+
+		writeEvent(StreamEventError, searchProblem)
+
+		return
+	}
+
+	writeEvent(StreamEventSummary, searchResponse(flightQuery, result, pagination.AtOffset(result.Flights, 0, len(result.Flights)), pageLinks{}))
+}
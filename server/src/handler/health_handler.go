@@ -3,18 +3,56 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
+	"sync"
+
+	"github.com/Orden14/flight-aggregator/src/repository"
 )
 
-type HealthHandler struct{}
+type HealthHandler struct {
+	mutex           sync.RWMutex
+	circuitBreakers []repository.CircuitStateReporter
+}
+
+type providerHealth struct {
+	Name    string                  `json:"name"`
+	Circuit repository.CircuitState `json:"circuit"`
+}
+
+func NewHealthHandler(circuitBreakers ...repository.CircuitStateReporter) *HealthHandler {
+	return &HealthHandler{circuitBreakers: circuitBreakers}
+}
+
+func (healthHandler *HealthHandler) SetCircuitBreakers(circuitBreakers ...repository.CircuitStateReporter) {
+	healthHandler.mutex.Lock()
+	defer healthHandler.mutex.Unlock()
 
-func NewHealthHandler() *HealthHandler {
-	return &HealthHandler{}
+	healthHandler.circuitBreakers = circuitBreakers
 }
 
 func (healthHandler *HealthHandler) ServeHTTP(writer http.ResponseWriter) {
+	healthHandler.mutex.RLock()
+	circuitBreakers := healthHandler.circuitBreakers
+	healthHandler.mutex.RUnlock()
+
+	status := "ok"
+	providers := make([]providerHealth, 0, len(circuitBreakers))
+
+	for _, circuitBreaker := range circuitBreakers {
+		circuitState := circuitBreaker.State()
+
+		if circuitState != repository.CircuitClosed {
+			status = "degraded"
+		}
+
+		providers = append(providers, providerHealth{Name: circuitBreaker.Name(), Circuit: circuitState})
+	}
+
 	writer.Header().Set("Content-Type", "application/json")
 
 	writer.WriteHeader(http.StatusOK)
 
-	_ = json.NewEncoder(writer).Encode(map[string]string{"status": "ok"})
+	_ = json.NewEncoder(writer).Encode(map[string]any{
+		"status":    status,
+		"providers": providers,
+	})
 }
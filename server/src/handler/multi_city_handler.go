@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Orden14/flight-aggregator/src/problem"
+)
+
+// ServeMultiCity answers POST /flights/multi-city with the best combinations of one
+// flight per leg under the requested objective.
+func (flightHandler *FlightHandler) ServeMultiCity(writer http.ResponseWriter, request *http.Request) {
+	multiCityQuery, err := parseMultiCityQuery(http.MaxBytesReader(writer, request.Body, maxMultiCityBodyBytes), *flightHandler.defaults.Load())
+
+	if err != nil {
+		writeQueryProblem(writer, request, err)
+
+		return
+	}
+
+	result, err := flightHandler.flightService.SearchMultiCity(request.Context(), multiCityQuery)
+
+	if err != nil {
+		problem.Write(writer, request, searchProblem(err))
+
+		return
+	}
+
+	if cacheStatus := cacheStatusHeader(result.Providers); cacheStatus != "" {
+		writer.Header().Set("Cache-Status", cacheStatus)
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+
+	json.NewEncoder(writer).Encode(map[string]any{
+		"itineraries_count": len(result.Itineraries),
+		"objective":         multiCityQuery.Objective,
+		"mode":              multiCityQuery.Mode,
+		"truncated":         result.Truncated,
+		"providers":         result.Providers,
+		"items":             result.Itineraries,
+	})
+}
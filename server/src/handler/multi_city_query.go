@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Orden14/flight-aggregator/src/service"
+	"github.com/Orden14/flight-aggregator/src/util/sorter"
+)
+
+const (
+	maxMultiCityLegs      = 6
+	maxMultiCityLimit     = 50
+	maxMultiCityBodyBytes = 64 << 10
+)
+
+// Members of a multi-city body that make no sense for a single leg.
+var multiCityUnsupportedMembers = []string{"sort", "order", "offset", "cursor", "format", "interline"}
+
+// parseMultiCityQuery reads {"legs": [...], "objective": ...}. Filters next to legs apply
+// to every leg that does not set them.
+func parseMultiCityQuery(body io.Reader, defaults SearchDefaults) (service.MultiCityQuery, error) {
+	var members map[string]any
+
+	err := json.NewDecoder(body).Decode(&members)
+
+	var maxBytesError *http.MaxBytesError
+
+	if errors.As(err, &maxBytesError) {
+		return service.MultiCityQuery{}, &ValidationError{InvalidParams: []InvalidParam{{Name: "body", Reason: fmt.Sprintf("must not exceed %d bytes", maxBytesError.Limit)}}}
+	}
+
+	if err != nil || members == nil {
+		return service.MultiCityQuery{}, &ValidationError{InvalidParams: []InvalidParam{{Name: "body", Reason: "expected a JSON object with legs"}}}
+	}
+
+	sharedValues := make(url.Values)
+
+	for name, value := range members {
+		switch name {
+		case "legs", "objective", "limit":
+		default:
+			sharedValues.Set(name, formValue(value))
+		}
+	}
+
+	queryParser := newQueryParser(sharedValues, defaults)
+
+	multiCityQuery := service.MultiCityQuery{
+		Objective: sorter.SortByPrice,
+		Limit:     queryParser.parseMultiCityLimit(members["limit"]),
+		Mode:      queryParser.parseFetchMode("mode"),
+	}
+
+	if objective, isSet := members["objective"]; isSet {
+		sortBy, isValid := sorter.ParseSortBy(formValue(objective))
+
+		if !isValid || sortBy == sorter.SortByDepartureDate {
+			queryParser.reject("objective", formValue(objective), "expected price or travel_time")
+		}
+
+		multiCityQuery.Objective = sortBy
+	}
+
+	sharedValues.Del("mode")
+	sharedValues.Del("validation")
+
+	legs, isArray := members["legs"].([]any)
+
+	if !isArray || len(legs) < 2 || len(legs) > maxMultiCityLegs {
+		queryParser.reject("legs", "", fmt.Sprintf("expected between 2 and %d legs", maxMultiCityLegs))
+
+		return multiCityQuery, queryParser.err()
+	}
+
+	for legIndex, leg := range legs {
+		legQuery, invalidParams := parseLeg(fmt.Sprintf("legs[%d]", legIndex), leg, sharedValues, queryParser.mode, defaults)
+		queryParser.invalidParams = append(queryParser.invalidParams, invalidParams...)
+		multiCityQuery.Legs = append(multiCityQuery.Legs, legQuery)
+	}
+
+	return multiCityQuery, queryParser.err()
+}
+
+// parseLeg runs the /flights parser on the leg and reports its invalid parameters under
+// the leg's name, e.g. legs[1].from.
+func parseLeg(legName string, leg any, sharedValues url.Values, mode ValidationMode, defaults SearchDefaults) (service.FlightQuery, []InvalidParam) {
+	legMembers, isObject := leg.(map[string]any)
+
+	if !isObject {
+		return service.FlightQuery{}, []InvalidParam{{Name: legName, Value: formValue(leg), Reason: "expected an object"}}
+	}
+
+	legValues := make(url.Values, len(sharedValues)+len(legMembers))
+
+	for name, values := range sharedValues {
+		legValues[name] = values
+	}
+
+	for name, value := range legMembers {
+		legValues.Set(name, formValue(value))
+	}
+
+	legParser := &queryParser{query: legValues, mode: mode}
+
+	for _, name := range multiCityUnsupportedMembers {
+		if inputValue := legValues.Get(name); inputValue != "" {
+			legParser.reject(name, inputValue, "is not supported in a multi-city search")
+			legValues.Del(name)
+		}
+	}
+
+	for _, name := range []string{"mode", "validation", "limit"} {
+		if inputValue := legValues.Get(name); inputValue != "" {
+			legParser.reject(name, inputValue, "applies to the whole search, not to a leg")
+			legValues.Del(name)
+		}
+	}
+
+	legQuery, _ := legParser.parseSearch(defaults)
+
+	if legQuery.DepartureAirport == "" {
+		legParser.reject("from", "", "is required for every leg, unless near is set")
+	}
+
+	if legQuery.ArrivalAirport == "" {
+		legParser.reject("to", "", "is required for every leg")
+	}
+
+	for index := range legParser.invalidParams {
+		legParser.invalidParams[index].Name = legName + "." + legParser.invalidParams[index].Name
+	}
+
+	return legQuery, legParser.invalidParams
+}
+
+func (queryParser *queryParser) parseMultiCityLimit(limit any) int {
+	if limit == nil {
+		return 0
+	}
+
+	parsedLimit, err := strconv.Atoi(formValue(limit))
+
+	if err != nil || parsedLimit < 1 || parsedLimit > maxMultiCityLimit {
+		queryParser.reject("limit", formValue(limit), "expected an integer between 1 and "+strconv.Itoa(maxMultiCityLimit))
+
+		return 0
+	}
+
+	return parsedLimit
+}
+
+// formValue writes a JSON value the way it would appear in a query string, arrays
+// becoming comma-separated lists.
+func formValue(value any) string {
+	switch typedValue := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typedValue
+	case float64:
+		return strconv.FormatFloat(typedValue, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(typedValue)
+	case []any:
+		values := make([]string, 0, len(typedValue))
+
+		for _, element := range typedValue {
+			values = append(values, formValue(element))
+		}
+
+		return strings.Join(values, ",")
+	default:
+		encodedValue, _ := json.Marshal(typedValue)
+
+		return string(encodedValue)
+	}
+}
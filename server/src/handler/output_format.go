@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/domain"
+)
+
+type OutputFormat string
+
+const (
+	OutputFormatJSON   OutputFormat = "json"
+	OutputFormatNDJSON OutputFormat = "ndjson"
+	OutputFormatCSV    OutputFormat = "csv"
+)
+
+var outputContentTypes = map[OutputFormat]string{
+	OutputFormatJSON:   "application/json",
+	OutputFormatNDJSON: "application/x-ndjson",
+	OutputFormatCSV:    "text/csv; charset=utf-8",
+}
+
+var mediaTypeFormats = map[string]OutputFormat{
+	"application/json":     OutputFormatJSON,
+	"application/x-ndjson": OutputFormatNDJSON,
+	"application/ndjson":   OutputFormatNDJSON,
+	"text/csv":             OutputFormatCSV,
+	"application/*":        OutputFormatJSON,
+	"text/*":               OutputFormatCSV,
+	"*/*":                  OutputFormatJSON,
+}
+
+// CSV columns follow the JSON field names of domain.Flight. Segments and layovers are
+// flattened into "|"-separated lists, as are the tickets of a virtual interline itinerary.
+var flightColumns = []struct {
+	name  string
+	value func(flight domain.Flight) string
+}{
+	{"reference", func(flight domain.Flight) string { return flight.Reference }},
+	{"flightNumber", func(flight domain.Flight) string { return flight.FlightNumber }},
+	{"from", func(flight domain.Flight) string { return flight.From }},
+	{"to", func(flight domain.Flight) string { return flight.To }},
+	{"departureTime", func(flight domain.Flight) string { return flight.DepartureTime.Format(time.RFC3339) }},
+	{"arrivalTime", func(flight domain.Flight) string { return flight.ArrivalTime.Format(time.RFC3339) }},
+	{"price", func(flight domain.Flight) string { return strconv.FormatFloat(flight.Price, 'f', -1, 64) }},
+	{"currency", func(flight domain.Flight) string { return flight.Currency }},
+	{"travelTimeMinutes", func(flight domain.Flight) string { return strconv.Itoa(flight.TravelTimeMinutes) }},
+	{"stops", func(flight domain.Flight) string { return strconv.Itoa(flight.Stops) }},
+	{"segments", formatSegments},
+	{"layovers", formatLayovers},
+	{"virtualInterline", func(flight domain.Flight) string { return strconv.FormatBool(flight.VirtualInterline) }},
+	{"tickets", func(flight domain.Flight) string { return strings.Join(flight.Tickets, "|") }},
+}
+
+func ParseOutputFormat(inputValue string) (OutputFormat, bool) {
+	switch strings.ToLower(inputValue) {
+	case "json":
+		return OutputFormatJSON, true
+	case "ndjson", "jsonl":
+		return OutputFormatNDJSON, true
+	case "csv":
+		return OutputFormatCSV, true
+	default:
+		return OutputFormatJSON, false
+	}
+}
+
+// negotiateOutputFormat picks the supported media type with the highest quality in an
+// Accept header. An empty header means JSON.
+func negotiateOutputFormat(accept string) (OutputFormat, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return OutputFormatJSON, true
+	}
+
+	type candidate struct {
+		format  OutputFormat
+		quality float64
+	}
+
+	var candidates []candidate
+
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, parameters, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+
+		if err != nil {
+			continue
+		}
+
+		outputFormat, isSupported := mediaTypeFormats[mediaType]
+
+		if !isSupported {
+			continue
+		}
+
+		quality := 1.0
+
+		if qualityValue, hasQuality := parameters["q"]; hasQuality {
+			if quality, err = strconv.ParseFloat(qualityValue, 64); err != nil {
+				continue
+			}
+		}
+
+		if quality > 0 {
+			candidates = append(candidates, candidate{format: outputFormat, quality: quality})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return OutputFormatJSON, false
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].quality > candidates[j].quality })
+
+	return candidates[0].format, true
+}
+
+func writeNDJSON(writer io.Writer, flights []domain.Flight) error {
+	encoder := json.NewEncoder(writer)
+
+	for _, flight := range flights {
+		if err := encoder.Encode(flight); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeCSV(writer io.Writer, flights []domain.Flight) error {
+	csvWriter := csv.NewWriter(writer)
+
+	record := make([]string, len(flightColumns))
+
+	for index, column := range flightColumns {
+		record[index] = column.name
+	}
+
+	if err := csvWriter.Write(record); err != nil {
+		return err
+	}
+
+	for _, flight := range flights {
+		for index, column := range flightColumns {
+			record[index] = escapeFormula(column.value(flight))
+		}
+
+		if err := csvWriter.Write(record); err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+
+	return csvWriter.Error()
+}
+
+// escapeFormula quotes cells that spreadsheets would evaluate as a formula.
+func escapeFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+
+	return cell
+}
+
+func formatSegments(flight domain.Flight) string {
+	segments := make([]string, 0, len(flight.Segments))
+
+	for _, segment := range flight.Segments {
+		segments = append(segments, segment.FlightNumber+" "+segment.From+"-"+segment.To)
+	}
+
+	return strings.Join(segments, "|")
+}
+
+func formatLayovers(flight domain.Flight) string {
+	layovers := make([]string, 0, len(flight.Layovers))
+
+	for _, layover := range flight.Layovers {
+		layovers = append(layovers, layover.Airport+" "+strconv.Itoa(layover.DurationMinutes)+"m")
+	}
+
+	return strings.Join(layovers, "|")
+}
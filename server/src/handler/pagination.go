@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"errors"
+	"hash/fnv"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/Orden14/flight-aggregator/src/domain"
+	"github.com/Orden14/flight-aggregator/src/util/pagination"
+	"github.com/Orden14/flight-aggregator/src/util/sorter"
+)
+
+const maxPageLimit = 500
+
+var errStaleCursor = errors.New("cursor was issued for an older result snapshot")
+
+// pageRequest is the pagination part of a search. A zero limit returns every flight.
+type pageRequest struct {
+	limit       int
+	offset      int
+	cursor      *pagination.Cursor
+	isOffset    bool
+	fingerprint uint64
+}
+
+type pageLinks struct {
+	Next string
+	Prev string
+}
+
+// searchFingerprint identifies the filters of a search, so that a cursor cannot be
+// replayed against another search. Sorting is checked separately.
+func searchFingerprint(query url.Values) uint64 {
+	filters := make(url.Values, len(query))
+
+	for parameter, values := range query {
+		switch parameter {
+		case "limit", "offset", "cursor", "format", "validation", "sort", "order":
+		default:
+			filters[parameter] = values
+		}
+	}
+
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(filters.Encode()))
+
+	return hash.Sum64()
+}
+
+func (queryParser *queryParser) parsePage(sortBy sorter.SortBy, sortOrder sorter.Order) pageRequest {
+	query := queryParser.query
+	page := pageRequest{fingerprint: searchFingerprint(query)}
+
+	if limit := query.Get("limit"); limit != "" {
+		parsedLimit, err := strconv.Atoi(limit)
+
+		if err != nil || parsedLimit < 1 || parsedLimit > maxPageLimit {
+			queryParser.reject("limit", limit, "expected an integer between 1 and "+strconv.Itoa(maxPageLimit))
+		} else {
+			page.limit = parsedLimit
+		}
+	}
+
+	if offset := query.Get("offset"); offset != "" {
+		parsedOffset, err := strconv.Atoi(offset)
+
+		if err != nil || parsedOffset < 0 {
+			queryParser.reject("offset", offset, "expected a non-negative integer")
+		} else {
+			page.offset = parsedOffset
+			page.isOffset = true
+		}
+	}
+
+	encodedCursor := query.Get("cursor")
+
+	if encodedCursor == "" {
+		return page
+	}
+
+	if query.Get("offset") != "" {
+		queryParser.reject("cursor", encodedCursor, "cannot be combined with offset")
+
+		return page
+	}
+
+	cursor, err := pagination.DecodeCursor(encodedCursor)
+
+	switch {
+	case err != nil:
+		queryParser.reject("cursor", encodedCursor, "expected a cursor returned in a next or prev link")
+	case cursor.Fingerprint != page.fingerprint:
+		queryParser.reject("cursor", encodedCursor, "was issued for a search with different filters")
+	case cursor.SortBy != sortBy || cursor.Order != sortOrder:
+		queryParser.reject("cursor", encodedCursor, "was issued for sort="+string(cursor.SortBy)+" and order="+string(cursor.Order))
+	default:
+		page.cursor = &cursor
+	}
+
+	return page
+}
+
+func (page pageRequest) paginate(flights []domain.Flight, generation uint64) (pagination.Page, error) {
+	limit := page.limit
+
+	if limit == 0 {
+		limit = len(flights)
+	}
+
+	if page.cursor == nil {
+		return pagination.AtOffset(flights, page.offset, limit), nil
+	}
+
+	if page.cursor.Generation != generation {
+		return pagination.Page{}, errStaleCursor
+	}
+
+	return pagination.AfterCursor(flights, *page.cursor, limit), nil
+}
+
+// bounds cuts an offset page out of results that are not paginated by cursor.
+func (page pageRequest) bounds(total int) (int, int) {
+	limit := page.limit
+
+	if limit == 0 {
+		limit = total
+	}
+
+	start := min(page.offset, total)
+
+	return start, min(start+limit, total)
+}
+
+// links keep every parameter of the request and only move the page. Offset requests get
+// offset links, every other paginated request gets cursor links.
+func (page pageRequest) links(requestURL *url.URL, result pagination.Page, sortBy sorter.SortBy, sortOrder sorter.Order, generation uint64) pageLinks {
+	if page.limit == 0 {
+		return pageLinks{}
+	}
+
+	link := func(update func(query url.Values)) string {
+		query := requestURL.Query()
+		query.Del("offset")
+		query.Del("cursor")
+		query.Set("limit", strconv.Itoa(page.limit))
+		update(query)
+
+		return requestURL.Path + "?" + query.Encode()
+	}
+
+	cursorLink := func(flight domain.Flight, backward bool) string {
+		cursor := pagination.CursorAt(flight, sortBy, sortOrder, backward)
+		cursor.Generation = generation
+		cursor.Fingerprint = page.fingerprint
+
+		return link(func(query url.Values) { query.Set("cursor", cursor.Encode()) })
+	}
+
+	var links pageLinks
+
+	if result.HasNext {
+		if page.isOffset {
+			links.Next = link(func(query url.Values) { query.Set("offset", strconv.Itoa(result.Start+page.limit)) })
+		} else {
+			links.Next = cursorLink(result.Flights[len(result.Flights)-1], false)
+		}
+	}
+
+	if result.HasPrev {
+		switch {
+		case page.isOffset:
+			links.Prev = link(func(query url.Values) { query.Set("offset", strconv.Itoa(max(result.Start-page.limit, 0))) })
+		case len(result.Flights) > 0:
+			links.Prev = cursorLink(result.Flights[0], true)
+		}
+	}
+
+	return links
+}
+
+func (links pageLinks) setHeaders(header http.Header, total int) {
+	header.Set("X-Total-Count", strconv.Itoa(total))
+
+	if links.Next != "" {
+		header.Add("Link", "<"+links.Next+">; rel=\"next\"")
+	}
+
+	if links.Prev != "" {
+		header.Add("Link", "<"+links.Prev+">; rel=\"prev\"")
+	}
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Orden14/flight-aggregator/src/problem"
+	"github.com/Orden14/flight-aggregator/src/util/pagination"
+)
+
+// ServeRoundTrips answers /flights/round-trip with outbound and return flights paired
+// into itineraries, in JSON only.
+func (flightHandler *FlightHandler) ServeRoundTrips(writer http.ResponseWriter, request *http.Request) {
+	roundTripQuery, page, err := parseRoundTripQuery(request.URL.Query(), *flightHandler.defaults.Load())
+
+	if err != nil {
+		writeQueryProblem(writer, request, err)
+
+		return
+	}
+
+	result, err := flightHandler.flightService.SearchRoundTrips(request.Context(), roundTripQuery)
+
+	if err != nil {
+		problem.Write(writer, request, searchProblem(err))
+
+		return
+	}
+
+	start, end := page.bounds(len(result.Itineraries))
+	resultPage := pagination.Page{Start: start, Total: len(result.Itineraries), HasPrev: start > 0, HasNext: end < len(result.Itineraries)}
+
+	links := page.links(request.URL, resultPage, roundTripQuery.SortBy, roundTripQuery.SortOrder, result.Generation)
+	links.setHeaders(writer.Header(), resultPage.Total)
+
+	if cacheStatus := cacheStatusHeader(result.Providers); cacheStatus != "" {
+		writer.Header().Set("Cache-Status", cacheStatus)
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+
+	json.NewEncoder(writer).Encode(map[string]any{
+		"itineraries_count": end - start,
+		"total":             resultPage.Total,
+		"offset":            start,
+		"next":              optionalLink(links.Next),
+		"prev":              optionalLink(links.Prev),
+		"sort_by":           roundTripQuery.SortBy,
+		"sort_order":        roundTripQuery.SortOrder,
+		"mode":              roundTripQuery.Mode,
+		"truncated":         result.Truncated,
+		"providers":         result.Providers,
+		"items":             result.Itineraries[start:end],
+	})
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/service"
+)
+
+const maxStay = 365 * 24 * time.Hour
+
+// parseRoundTripQuery applies the price bounds to the round-trip total.
+func parseRoundTripQuery(query url.Values, defaults SearchDefaults) (service.RoundTripQuery, pageRequest, error) {
+	queryParser := newQueryParser(query, defaults)
+	queryParser.rejectUnsupported("round trips are paginated with limit and offset", "cursor")
+	queryParser.rejectUnsupported("is not supported by round trips", "interline")
+	roundTripQuery, page := queryParser.parseRoundTrip(defaults)
+
+	if roundTripQuery.Outbound.DepartureAirport == "" {
+		queryParser.reject("from", "", "is required for a round trip, unless near is set")
+	}
+
+	if roundTripQuery.Outbound.ArrivalAirport == "" {
+		queryParser.reject("to", "", "is required for a round trip")
+	}
+
+	return roundTripQuery, page, queryParser.err()
+}
+
+func (queryParser *queryParser) parseRoundTrip(defaults SearchDefaults) (service.RoundTripQuery, pageRequest) {
+	query := queryParser.query
+	outboundQuery, page := queryParser.parseSearch(defaults)
+	page.isOffset = true
+
+	roundTripQuery := service.RoundTripQuery{
+		Outbound:  outboundQuery,
+		MinPrice:  outboundQuery.MinPrice,
+		MaxPrice:  outboundQuery.MaxPrice,
+		MinStay:   queryParser.parseStay("min_stay"),
+		MaxStay:   queryParser.parseStay("max_stay"),
+		SortBy:    outboundQuery.SortBy,
+		SortOrder: outboundQuery.SortOrder,
+		Mode:      outboundQuery.Mode,
+	}
+
+	roundTripQuery.Outbound.MinPrice = nil
+	roundTripQuery.Outbound.MaxPrice = nil
+
+	returnQuery := roundTripQuery.Outbound
+	returnQuery.DepartureAirport, returnQuery.ArrivalAirport = outboundQuery.ArrivalAirport, outboundQuery.DepartureAirport
+	returnQuery.DepartureRadiusKm, returnQuery.ArrivalRadiusKm = outboundQuery.ArrivalRadiusKm, outboundQuery.DepartureRadiusKm
+	returnQuery.DepartureDateFrom, returnQuery.DepartureDateTo = queryParser.parseDateRange("return_date")
+	returnQuery.DepartureWindow = queryParser.parseTimeWindow("return_time_from", "return_time_to")
+	returnQuery.ArrivalWindow = nil
+	returnQuery.FlightNumber = ""
+	roundTripQuery.Return = returnQuery
+
+	if roundTripQuery.MinStay > 0 && roundTripQuery.MaxStay > 0 && roundTripQuery.MinStay > roundTripQuery.MaxStay {
+		queryParser.reject("min_stay", query.Get("min_stay"), "must not be above max_stay")
+	}
+
+	return roundTripQuery, page
+}
+
+// parseStay accepts a number of days ("3") or a Go duration ("36h").
+func (queryParser *queryParser) parseStay(parameter string) time.Duration {
+	inputValue := queryParser.query.Get(parameter)
+
+	if inputValue == "" {
+		return 0
+	}
+
+	duration, err := time.ParseDuration(inputValue)
+
+	if days, daysErr := strconv.Atoi(inputValue); daysErr == nil {
+		// Capped before the conversion, which would otherwise overflow.
+		duration, err = time.Duration(min(days, int(maxStay/(24*time.Hour))+1))*24*time.Hour, nil
+	}
+
+	if err != nil || duration < 0 {
+		queryParser.reject(parameter, inputValue, "expected days or a duration such as 36h")
+
+		return 0
+	}
+
+	if duration > maxStay {
+		queryParser.reject(parameter, inputValue, "must not exceed 365 days")
+
+		return 0
+	}
+
+	return duration
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Orden14/flight-aggregator/src/problem"
+	"github.com/Orden14/flight-aggregator/src/repository"
+	"github.com/Orden14/flight-aggregator/src/service"
+)
+
+// searchProblem maps a failed search to a problem without exposing upstream URLs or
+// payloads: the detail only names the category, the failing providers are an extension.
+func searchProblem(err error) *problem.Problem {
+	var searchProblem *problem.Problem
+
+	switch {
+	case errors.Is(err, service.ErrFlightNotFound):
+		searchProblem = problem.New(problem.TypeFlightNotFound, http.StatusNotFound, "no provider offers this flight")
+	case errors.Is(err, service.ErrNoRepositories):
+		searchProblem = problem.New(problem.TypeProvidersUnavailable, http.StatusServiceUnavailable, "no flight provider is configured")
+	case errors.Is(err, repository.ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		searchProblem = problem.New(problem.TypeProviderTimeout, http.StatusGatewayTimeout, "a flight provider did not answer in time")
+	case errors.Is(err, repository.ErrCircuitOpen):
+		searchProblem = problem.New(problem.TypeProvidersUnavailable, http.StatusServiceUnavailable, "a flight provider is temporarily disabled after repeated failures")
+	case errors.Is(err, repository.ErrBadStatus):
+		searchProblem = problem.New(problem.TypeProviderError, http.StatusBadGateway, "a flight provider answered with an unexpected status")
+	case errors.Is(err, repository.ErrDecode):
+		searchProblem = problem.New(problem.TypeProviderError, http.StatusBadGateway, "a flight provider returned an unreadable payload")
+	default:
+		searchProblem = problem.New(problem.TypeProviderError, http.StatusBadGateway, "failed to fetch flights")
+	}
+
+	var providerFailureError *service.ProviderFailureError
+
+	if errors.As(err, &providerFailureError) {
+		searchProblem.With("providers", providerFailureError.Providers)
+	}
+
+	return searchProblem
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Orden14/flight-aggregator/src/problem"
+)
+
+type ValidationMode string
+
+const (
+	ValidationStrict  ValidationMode = "strict"
+	ValidationLenient ValidationMode = "lenient"
+)
+
+func ParseValidationMode(inputValue string) (ValidationMode, bool) {
+	switch strings.ToLower(inputValue) {
+	case "strict":
+		return ValidationStrict, true
+	case "lenient":
+		return ValidationLenient, true
+	default:
+		return ValidationStrict, false
+	}
+}
+
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Value  string `json:"value,omitempty"`
+	Reason string `json:"reason"`
+}
+
+type ValidationError struct {
+	InvalidParams []InvalidParam `json:"invalid_params"`
+}
+
+func (validationError *ValidationError) Error() string {
+	reasons := make([]string, 0, len(validationError.InvalidParams))
+
+	for _, invalidParam := range validationError.InvalidParams {
+		reasons = append(reasons, "invalid "+invalidParam.Name+": "+invalidParam.Reason)
+	}
+
+	return strings.Join(reasons, "; ")
+}
+
+func validationProblem(validationError *ValidationError) *problem.Problem {
+	return problem.New(problem.TypeInvalidParameters, http.StatusBadRequest, validationError.Error()).
+		With("invalid_params", validationError.InvalidParams)
+}
@@ -4,15 +4,15 @@ import (
 	"net/http"
 
 	"github.com/Orden14/flight-aggregator/src/handler"
+	"github.com/Orden14/flight-aggregator/src/problem"
 )
 
-func NewRouter(healthHandler *handler.HealthHandler, flightHandler *handler.FlightHandler) http.Handler {
+func NewRouter(healthHandler *handler.HealthHandler, flightHandler *handler.FlightHandler, adminHandler *handler.AdminHandler) http.Handler {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("/health", func(writer http.ResponseWriter, request *http.Request) {
 		if request.Method != http.MethodGet {
-			writer.Header().Set("Allow", http.MethodGet)
-			http.Error(writer, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			methodNotAllowed(writer, request, http.MethodGet)
 
 			return
 		}
@@ -22,8 +22,7 @@ func NewRouter(healthHandler *handler.HealthHandler, flightHandler *handler.Flig
 
 	mux.HandleFunc("/flights", func(writer http.ResponseWriter, request *http.Request) {
 		if request.Method != http.MethodGet {
-			writer.Header().Set("Allow", http.MethodGet)
-			http.Error(writer, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			methodNotAllowed(writer, request, http.MethodGet)
 
 			return
 		}
@@ -31,5 +30,74 @@ func NewRouter(healthHandler *handler.HealthHandler, flightHandler *handler.Flig
 		flightHandler.ServeHTTP(writer, request)
 	})
 
+	mux.HandleFunc("/flights/stream", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodGet {
+			methodNotAllowed(writer, request, http.MethodGet)
+
+			return
+		}
+
+		flightHandler.ServeStream(writer, request)
+	})
+
+	mux.HandleFunc("/flights/round-trip", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodGet {
+			methodNotAllowed(writer, request, http.MethodGet)
+
+			return
+		}
+
+		flightHandler.ServeRoundTrips(writer, request)
+	})
+
+	mux.HandleFunc("/flights/multi-city", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodPost {
+			methodNotAllowed(writer, request, http.MethodPost)
+
+			return
+		}
+
+		flightHandler.ServeMultiCity(writer, request)
+	})
+
+	mux.HandleFunc("/flights/calendar", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodGet {
+			methodNotAllowed(writer, request, http.MethodGet)
+
+			return
+		}
+
+		flightHandler.ServeCalendar(writer, request)
+	})
+
+	mux.HandleFunc("/flights/{reference}", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodGet {
+			methodNotAllowed(writer, request, http.MethodGet)
+
+			return
+		}
+
+		flightHandler.ServeDetail(writer, request)
+	})
+
+	mux.HandleFunc("/admin/config", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodGet {
+			methodNotAllowed(writer, request, http.MethodGet)
+
+			return
+		}
+
+		adminHandler.ServeHTTP(writer, request)
+	})
+
+	mux.HandleFunc("/", func(writer http.ResponseWriter, request *http.Request) {
+		problem.Write(writer, request, problem.New("", http.StatusNotFound, "no route for "+request.URL.Path))
+	})
+
 	return mux
 }
+
+func methodNotAllowed(writer http.ResponseWriter, request *http.Request, allowedMethod string) {
+	writer.Header().Set("Allow", allowedMethod)
+	problem.Write(writer, request, problem.New(problem.TypeMethodNotAllowed, http.StatusMethodNotAllowed, request.Method+" is not supported on "+request.URL.Path))
+}
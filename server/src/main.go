@@ -3,12 +3,13 @@ package main
 import (
 	"log"
 	"net/http"
+	"sync"
 
 	"github.com/Orden14/flight-aggregator/src/config"
 	"github.com/Orden14/flight-aggregator/src/handler"
 	"github.com/Orden14/flight-aggregator/src/httpserver"
-	"github.com/Orden14/flight-aggregator/src/repository"
 	"github.com/Orden14/flight-aggregator/src/service"
+	"github.com/Orden14/flight-aggregator/src/util/sorter"
 	"github.com/spf13/viper"
 )
 
@@ -19,14 +20,54 @@ func main() {
 		log.Fatal("config error: ", err)
 	}
 
-	r1 := repository.NewServer1FlightRepository(cfg.JServer1)
-	r2 := repository.NewServer2FlightRepository(cfg.JServer2)
+	registry := &providerRegistry{}
 
-	svc := service.NewFlightService(5, r1, r2)
+	repositories, circuitBreakers, err := registry.build(cfg)
 
-	health := handler.NewHealthHandler()
+	if err != nil {
+		log.Fatal("provider error: ", err)
+	}
+
+	svc := service.NewFlightServiceWithOptions(serviceOptions(cfg), repositories...)
+
+	health := handler.NewHealthHandler(circuitBreakers...)
 	flight := handler.NewFlightHandler(svc)
-	router := httpserver.NewRouter(health, flight)
+	flight.SetDefaults(searchDefaults(cfg))
+
+	admin := handler.NewAdminHandler(config.FileUsed(), providerNames(repositories))
+	admin.SetToken(cfg.AdminToken)
+
+	var reloadMutex sync.Mutex
+
+	isWatching := config.Watch(func(reloadedConfig *config.AppConfig, err error) {
+		reloadMutex.Lock()
+		defer reloadMutex.Unlock()
+
+		if err == nil {
+			repositories, circuitBreakers, err = registry.build(reloadedConfig)
+		}
+
+		if err != nil {
+			log.Println("config reload failed: ", err)
+			admin.RecordReload(nil, err)
+
+			return
+		}
+
+		svc.Reload(serviceOptions(reloadedConfig), repositories...)
+		health.SetCircuitBreakers(circuitBreakers...)
+		flight.SetDefaults(searchDefaults(reloadedConfig))
+		admin.SetToken(reloadedConfig.AdminToken)
+		admin.RecordReload(providerNames(repositories), nil)
+
+		log.Println("config reloaded, providers:", providerNames(repositories))
+	})
+
+	if isWatching {
+		log.Println("watching config file", config.FileUsed())
+	}
+
+	router := httpserver.NewRouter(health, flight, admin)
 
 	var addr string
 
@@ -42,3 +83,22 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+func serviceOptions(cfg *config.AppConfig) service.FlightServiceOptions {
+	return service.FlightServiceOptions{
+		RepositoryTimeout:         cfg.RepositoryTimeout,
+		CacheTTL:                  cfg.Cache.TTL,
+		CacheStaleWhileRevalidate: cfg.Cache.StaleWhileRevalidate,
+		CacheStaleIfError:         cfg.Cache.StaleIfError,
+	}
+}
+
+func searchDefaults(cfg *config.AppConfig) handler.SearchDefaults {
+	validationMode, _ := handler.ParseValidationMode(cfg.SearchDefaults.Validation)
+
+	return handler.SearchDefaults{
+		SortBy:     sorter.NormalizeSortBy(cfg.SearchDefaults.SortBy),
+		SortOrder:  sorter.NormalizeOrder(cfg.SearchDefaults.Order),
+		Validation: validationMode,
+	}
+}
@@ -0,0 +1,107 @@
+package problem
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"net/http"
+)
+
+const ContentType = "application/problem+json"
+
+const RequestIDHeader = "X-Request-Id"
+
+const (
+	TypeInvalidParameters    = "/problems/invalid-parameters"
+	TypeProviderTimeout      = "/problems/provider-timeout"
+	TypeProviderError        = "/problems/provider-error"
+	TypeProvidersUnavailable = "/problems/providers-unavailable"
+	TypeMethodNotAllowed     = "/problems/method-not-allowed"
+	TypeNotAcceptable        = "/problems/not-acceptable"
+	TypeStaleCursor          = "/problems/stale-cursor"
+	TypeFlightNotFound       = "/problems/flight-not-found"
+)
+
+// Problem is an RFC 7807 problem details object. Extensions are serialized as
+// top-level members next to the standard ones.
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]any
+}
+
+func New(problemType string, status int, detail string) *Problem {
+	return &Problem{
+		Type:   problemType,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+func (problem *Problem) With(key string, value any) *Problem {
+	if problem.Extensions == nil {
+		problem.Extensions = make(map[string]any)
+	}
+
+	problem.Extensions[key] = value
+
+	return problem
+}
+
+func (problem *Problem) MarshalJSON() ([]byte, error) {
+	members := make(map[string]any, len(problem.Extensions)+5)
+	maps.Copy(members, problem.Extensions)
+
+	members["type"] = problem.Type
+	members["title"] = problem.Title
+	members["status"] = problem.Status
+
+	if problem.Type == "" {
+		members["type"] = "about:blank"
+	}
+
+	if problem.Detail != "" {
+		members["detail"] = problem.Detail
+	}
+
+	if problem.Instance != "" {
+		members["instance"] = problem.Instance
+	}
+
+	return json.Marshal(members)
+}
+
+// Write renders the problem for the request, tagging it with the request id so that
+// clients can quote it when reporting the failure.
+func Write(writer http.ResponseWriter, request *http.Request, problem *Problem) {
+	requestID := RequestID(request)
+
+	problem.With("request_id", requestID)
+
+	if problem.Instance == "" {
+		problem.Instance = request.URL.Path
+	}
+
+	writer.Header().Set(RequestIDHeader, requestID)
+	writer.Header().Set("Content-Type", ContentType)
+	writer.WriteHeader(problem.Status)
+
+	json.NewEncoder(writer).Encode(problem)
+}
+
+// RequestID returns the id sent by the client, or a random one.
+func RequestID(request *http.Request) string {
+	if requestID := request.Header.Get(RequestIDHeader); requestID != "" {
+		return requestID
+	}
+
+	randomBytes := make([]byte, 8)
+	_, _ = rand.Read(randomBytes)
+
+	return hex.EncodeToString(randomBytes)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"reflect"
+
+	"github.com/Orden14/flight-aggregator/src/config"
+	"github.com/Orden14/flight-aggregator/src/repository"
+)
+
+type registeredProvider struct {
+	providerConfig config.ProviderConfig
+	circuitBreaker *repository.CircuitBreakerRepository
+}
+
+type providerRegistry struct {
+	retryConfig          config.RetryConfig
+	circuitBreakerConfig config.CircuitBreakerConfig
+	providers            map[string]registeredProvider
+}
+
+// build keeps the circuit breaker of every provider whose settings did not change,
+// so a reload does not reset the failure history of untouched providers.
+func (registry *providerRegistry) build(cfg *config.AppConfig) ([]repository.FlightRepositoryInterface, []repository.CircuitStateReporter, error) {
+	retryPolicy := repository.NewRetryPolicy(cfg.Retry)
+	isSameSettings := reflect.DeepEqual(registry.retryConfig, cfg.Retry) && registry.circuitBreakerConfig == cfg.CircuitBreaker
+
+	providers := make(map[string]registeredProvider, len(cfg.Providers))
+
+	var repositories []repository.FlightRepositoryInterface
+	var circuitBreakers []repository.CircuitStateReporter
+
+	for _, providerConfig := range cfg.Providers {
+		if !providerConfig.IsEnabled() {
+			continue
+		}
+
+		provider, isRegistered := registry.providers[providerConfig.Name]
+
+		if !isRegistered || !isSameSettings || !reflect.DeepEqual(provider.providerConfig, providerConfig) {
+			flightRepository, err := repository.NewProviderRepository(providerConfig, retryPolicy)
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			provider = registeredProvider{
+				providerConfig: providerConfig,
+				circuitBreaker: repository.NewCircuitBreakerRepository(flightRepository, cfg.CircuitBreaker),
+			}
+		}
+
+		providers[providerConfig.Name] = provider
+		repositories = append(repositories, provider.circuitBreaker)
+		circuitBreakers = append(circuitBreakers, provider.circuitBreaker)
+	}
+
+	registry.retryConfig = cfg.Retry
+	registry.circuitBreakerConfig = cfg.CircuitBreaker
+	registry.providers = providers
+
+	return repositories, circuitBreakers, nil
+}
+
+func providerNames(repositories []repository.FlightRepositoryInterface) []string {
+	names := make([]string, 0, len(repositories))
+
+	for _, flightRepository := range repositories {
+		names = append(names, flightRepository.Name())
+	}
+
+	return names
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/config"
+	"github.com/Orden14/flight-aggregator/src/domain"
+)
+
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+type CircuitStateReporter interface {
+	Name() string
+	State() CircuitState
+}
+
+type CircuitBreakerRepository struct {
+	repository       FlightRepositoryInterface
+	failureThreshold int
+	coolDown         time.Duration
+
+	mutex               sync.Mutex
+	state               CircuitState
+	consecutiveFailures int
+	openedAt            time.Time
+	probeInFlight       bool
+	generation          uint64
+}
+
+func NewCircuitBreakerRepository(repository FlightRepositoryInterface, breakerConfig config.CircuitBreakerConfig) *CircuitBreakerRepository {
+	failureThreshold := breakerConfig.FailureThreshold
+
+	if failureThreshold <= 0 {
+		failureThreshold = 1
+	}
+
+	return &CircuitBreakerRepository{
+		repository:       repository,
+		failureThreshold: failureThreshold,
+		coolDown:         breakerConfig.CoolDown,
+		state:            CircuitClosed,
+	}
+}
+
+func (circuitBreaker *CircuitBreakerRepository) Name() string {
+	return circuitBreaker.repository.Name()
+}
+
+func (circuitBreaker *CircuitBreakerRepository) State() CircuitState {
+	circuitBreaker.mutex.Lock()
+	defer circuitBreaker.mutex.Unlock()
+
+	if circuitBreaker.state == CircuitOpen && time.Since(circuitBreaker.openedAt) >= circuitBreaker.coolDown {
+		return CircuitHalfOpen
+	}
+
+	return circuitBreaker.state
+}
+
+func (circuitBreaker *CircuitBreakerRepository) Fetch(ctx context.Context) ([]domain.Flight, error) {
+	report, err := circuitBreaker.FetchReport(ctx)
+
+	return report.Flights, err
+}
+
+func (circuitBreaker *CircuitBreakerRepository) FetchReport(ctx context.Context) (FetchReport, error) {
+	admission, err := circuitBreaker.acquire()
+
+	if err != nil {
+		return FetchReport{}, err
+	}
+
+	report, err := FetchWithReport(ctx, circuitBreaker.repository)
+
+	circuitBreaker.record(admission, err)
+
+	return report, err
+}
+
+// circuitAdmission remembers the state a call was admitted in, so that a call admitted
+// while closed cannot settle a half-open probe it did not make.
+type circuitAdmission struct {
+	generation uint64
+	isProbe    bool
+}
+
+func (circuitBreaker *CircuitBreakerRepository) acquire() (circuitAdmission, error) {
+	circuitBreaker.mutex.Lock()
+	defer circuitBreaker.mutex.Unlock()
+
+	switch circuitBreaker.state {
+	case CircuitOpen:
+		if time.Since(circuitBreaker.openedAt) < circuitBreaker.coolDown {
+			return circuitAdmission{}, &ProviderError{Provider: circuitBreaker.repository.Name(), Category: ErrorCategoryCircuitOpen}
+		}
+
+		circuitBreaker.transition(CircuitHalfOpen)
+		circuitBreaker.probeInFlight = true
+
+		return circuitAdmission{generation: circuitBreaker.generation, isProbe: true}, nil
+	case CircuitHalfOpen:
+		if circuitBreaker.probeInFlight {
+			return circuitAdmission{}, &ProviderError{Provider: circuitBreaker.repository.Name(), Category: ErrorCategoryCircuitOpen}
+		}
+
+		circuitBreaker.probeInFlight = true
+
+		return circuitAdmission{generation: circuitBreaker.generation, isProbe: true}, nil
+	}
+
+	return circuitAdmission{generation: circuitBreaker.generation}, nil
+}
+
+func (circuitBreaker *CircuitBreakerRepository) record(admission circuitAdmission, err error) {
+	circuitBreaker.mutex.Lock()
+	defer circuitBreaker.mutex.Unlock()
+
+	if admission.generation != circuitBreaker.generation {
+		return
+	}
+
+	if admission.isProbe {
+		circuitBreaker.probeInFlight = false
+	}
+
+	if err == nil {
+		circuitBreaker.transition(CircuitClosed)
+		circuitBreaker.consecutiveFailures = 0
+
+		return
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	circuitBreaker.consecutiveFailures++
+
+	if admission.isProbe || circuitBreaker.consecutiveFailures >= circuitBreaker.failureThreshold {
+		circuitBreaker.transition(CircuitOpen)
+		circuitBreaker.openedAt = time.Now()
+	}
+}
+
+func (circuitBreaker *CircuitBreakerRepository) transition(state CircuitState) {
+	if circuitBreaker.state != state {
+		circuitBreaker.state = state
+		circuitBreaker.generation++
+	}
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Orden14/flight-aggregator/src/domain"
+)
+
+const maxReportedIssues = 20
+
+type RecordIssue struct {
+	Reference string `json:"reference,omitempty"`
+	Field     string `json:"field"`
+	Reason    string `json:"reason"`
+}
+
+// FetchReport holds the flights, their raw records at the same index, and a capped list
+// of the skipped ones.
+type FetchReport struct {
+	Flights        []domain.Flight
+	Raw            []json.RawMessage
+	SkippedRecords int
+	Issues         []RecordIssue
+}
+
+type ReportingRepository interface {
+	FetchReport(ctx context.Context) (FetchReport, error)
+}
+
+func FetchWithReport(ctx context.Context, flightRepository FlightRepositoryInterface) (FetchReport, error) {
+	if reportingRepository, ok := flightRepository.(ReportingRepository); ok {
+		return reportingRepository.FetchReport(ctx)
+	}
+
+	flights, err := flightRepository.Fetch(ctx)
+
+	return FetchReport{Flights: flights}, err
+}
+
+// Record issues are shown to clients, so their reason is a fixed text rather than the
+// parse error, which may quote upstream values.
+const (
+	reasonMalformedRecord = "malformed record"
+	reasonNotAString      = "missing or not a string"
+	reasonNotANumber      = "missing or not a number"
+	reasonNotATime        = "missing or not an RFC 3339 date-time"
+	reasonNotAnArray      = "missing or not an array"
+)
+
+type fieldError struct {
+	field  string
+	reason string
+	err    error
+}
+
+func (fieldError *fieldError) Error() string {
+	return fieldError.field + ": " + fieldError.err.Error()
+}
+
+func (fieldError *fieldError) Unwrap() error {
+	return fieldError.err
+}
+
+type recordCollector struct {
+	provider httpProvider
+	report   FetchReport
+}
+
+func (provider httpProvider) newRecordCollector(capacity int) *recordCollector {
+	return &recordCollector{
+		provider: provider,
+		report:   FetchReport{Flights: make([]domain.Flight, 0, capacity), Raw: make([]json.RawMessage, 0, capacity)},
+	}
+}
+
+func (recordCollector *recordCollector) add(flight domain.Flight, raw json.RawMessage) {
+	recordCollector.report.Flights = append(recordCollector.report.Flights, flight)
+	recordCollector.report.Raw = append(recordCollector.report.Raw, raw)
+}
+
+// skip drops an invalid record. In strict parsing the first invalid record fails the fetch.
+func (recordCollector *recordCollector) skip(reference string, err error) error {
+	if recordCollector.provider.strictParsing {
+		return recordCollector.provider.decodeError(fmt.Errorf("record %q: %w", reference, err))
+	}
+
+	recordCollector.report.SkippedRecords++
+
+	if len(recordCollector.report.Issues) >= maxReportedIssues {
+		return nil
+	}
+
+	recordIssue := RecordIssue{Reference: reference, Reason: reasonMalformedRecord}
+
+	var fieldError *fieldError
+
+	if errors.As(err, &fieldError) {
+		recordIssue.Field = fieldError.field
+		recordIssue.Reason = fieldError.reason
+	}
+
+	recordCollector.report.Issues = append(recordCollector.report.Issues, recordIssue)
+
+	return nil
+}
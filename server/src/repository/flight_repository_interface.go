@@ -7,5 +7,6 @@ import (
 )
 
 type FlightRepositoryInterface interface {
+	Name() string
 	Fetch(ctx context.Context) ([]domain.Flight, error)
 }
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/config"
+)
+
+type httpProvider struct {
+	name        string
+	url         string
+	timeout     time.Duration
+	header      http.Header
+	client      *http.Client
+	retryPolicy RetryPolicy
+
+	strictParsing bool
+}
+
+func newHTTPProvider(providerConfig config.ProviderConfig, defaultPath string, retryPolicy RetryPolicy) httpProvider {
+	path := providerConfig.Path
+
+	if path == "" {
+		path = defaultPath
+	}
+
+	return httpProvider{
+		name:        providerConfig.Name,
+		url:         providerConfig.BaseURL + "/" + strings.TrimPrefix(path, "/"),
+		timeout:     providerConfig.Timeout,
+		header:      authHeader(providerConfig.Auth),
+		client:      &http.Client{Timeout: 0},
+		retryPolicy: retryPolicy,
+
+		strictParsing: providerConfig.Parsing == config.ParsingStrict,
+	}
+}
+
+func authHeader(authConfig config.AuthConfig) http.Header {
+	header := make(http.Header)
+
+	switch authConfig.Type {
+	case config.AuthTypeBearer:
+		header.Set("Authorization", "Bearer "+authConfig.Token)
+	case config.AuthTypeBasic:
+		credentials := base64.StdEncoding.EncodeToString([]byte(authConfig.Username + ":" + authConfig.Password))
+		header.Set("Authorization", "Basic "+credentials)
+	case config.AuthTypeHeader:
+		header.Set(authConfig.Header, authConfig.Token)
+	}
+
+	return header
+}
+
+func (provider httpProvider) Name() string {
+	return provider.name
+}
+
+func (provider httpProvider) fetchJSON(ctx context.Context, target any) error {
+	if provider.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, provider.timeout)
+		defer cancel()
+	}
+
+	response, err := getWithRetry(ctx, provider.client, provider.url, provider.header, provider.retryPolicy)
+
+	if err != nil {
+		if isTimeout(err) {
+			return provider.providerError(ErrorCategoryTimeout, err)
+		}
+
+		return provider.providerError(ErrorCategoryTransport, err)
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 1<<14))
+
+		providerError := provider.providerError(ErrorCategoryBadStatus, nil)
+		providerError.StatusCode = response.StatusCode
+
+		providerError.Body = strings.TrimSpace(string(body))
+
+		return providerError
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(target); err != nil {
+		return provider.decodeError(err)
+	}
+
+	return nil
+}
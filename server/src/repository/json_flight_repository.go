@@ -0,0 +1,295 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/config"
+	"github.com/Orden14/flight-aggregator/src/domain"
+)
+
+type JSONFlightRepository struct {
+	httpProvider
+	mapping config.FieldMappingConfig
+}
+
+func NewJSONFlightRepository(providerConfig config.ProviderConfig, retryPolicy RetryPolicy) (*JSONFlightRepository, error) {
+	if err := validateFieldMapping(providerConfig.Mapping); err != nil {
+		return nil, fmt.Errorf("provider %s: %w", providerConfig.Name, err)
+	}
+
+	return &JSONFlightRepository{
+		httpProvider: newHTTPProvider(providerConfig, "/", retryPolicy),
+		mapping:      providerConfig.Mapping,
+	}, nil
+}
+
+func validateFieldMapping(mapping config.FieldMappingConfig) error {
+	optionalFields := []struct{ name, pointer string }{
+		{"records", mapping.Records},
+		{"segments", mapping.Segments},
+	}
+
+	for _, field := range optionalFields {
+		if err := validatePointer(field.pointer); err != nil {
+			return fmt.Errorf("field mapping: %s: %w", field.name, err)
+		}
+	}
+
+	requiredFields := []struct{ name, pointer string }{
+		{"reference", mapping.Reference},
+		{"flight_number", mapping.FlightNumber},
+		{"from", mapping.From},
+		{"to", mapping.To},
+		{"departure_time", mapping.DepartureTime},
+		{"arrival_time", mapping.ArrivalTime},
+		{"price", mapping.Price},
+		{"currency", mapping.Currency},
+	}
+
+	for _, field := range requiredFields {
+		if field.pointer == "" {
+			return fmt.Errorf("field mapping: missing %s", field.name)
+		}
+
+		if err := validatePointer(field.pointer); err != nil {
+			return fmt.Errorf("field mapping: %s: %w", field.name, err)
+		}
+	}
+
+	return nil
+}
+
+func (flightRepository *JSONFlightRepository) Fetch(ctx context.Context) ([]domain.Flight, error) {
+	report, err := flightRepository.FetchReport(ctx)
+
+	return report.Flights, err
+}
+
+func (flightRepository *JSONFlightRepository) FetchReport(ctx context.Context) (FetchReport, error) {
+	var payload json.RawMessage
+
+	if err := flightRepository.fetchJSON(ctx, &payload); err != nil {
+		return FetchReport{}, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+
+	var document any
+
+	if err := decoder.Decode(&document); err != nil {
+		return FetchReport{}, flightRepository.decodeError(err)
+	}
+
+	recordsNode, err := resolvePointer(document, flightRepository.mapping.Records)
+
+	if err != nil {
+		return FetchReport{}, flightRepository.decodeError(fmt.Errorf("records: %w", err))
+	}
+
+	records, isArray := recordsNode.([]any)
+
+	if !isArray {
+		return FetchReport{}, flightRepository.decodeError(fmt.Errorf("records %q: not an array", flightRepository.mapping.Records))
+	}
+
+	recordCollector := flightRepository.newRecordCollector(len(records))
+
+	for _, record := range records {
+		flight, isMapped, err := flightRepository.mapRecord(record)
+
+		if err != nil {
+			reference, _ := stringAt(record, flightRepository.mapping.Reference)
+
+			if err := recordCollector.skip(reference, err); err != nil {
+				return FetchReport{}, err
+			}
+
+			continue
+		}
+
+		if isMapped {
+			raw, _ := json.Marshal(record)
+			recordCollector.add(flight, raw)
+		}
+	}
+
+	return recordCollector.report, nil
+}
+
+func (flightRepository *JSONFlightRepository) mapRecord(record any) (domain.Flight, bool, error) {
+	mapping := flightRepository.mapping
+
+	segmentNodes := []any{record}
+
+	if mapping.Segments != "" {
+		segmentsNode, err := resolvePointer(record, mapping.Segments)
+
+		if err != nil {
+			return domain.Flight{}, false, &fieldError{field: "segments", reason: reasonNotAnArray, err: err}
+		}
+
+		nestedSegments, isArray := segmentsNode.([]any)
+
+		if !isArray {
+			return domain.Flight{}, false, &fieldError{field: "segments", reason: reasonNotAnArray, err: fmt.Errorf("%q: not an array", mapping.Segments)}
+		}
+
+		if len(nestedSegments) == 0 {
+			return domain.Flight{}, false, nil
+		}
+
+		segmentNodes = nestedSegments
+	}
+
+	reference, err := stringAt(record, mapping.Reference)
+
+	if err != nil {
+		return domain.Flight{}, false, &fieldError{field: "reference", reason: reasonNotAString, err: err}
+	}
+
+	segments := make([]domain.Segment, 0, len(segmentNodes))
+
+	for index, segmentNode := range segmentNodes {
+		segment, err := mapSegment(segmentNode, mapping)
+
+		if err != nil {
+			if mapping.Segments != "" {
+				err.field = fmt.Sprintf("segments[%d].%s", index, err.field)
+			}
+
+			return domain.Flight{}, false, err
+		}
+
+		segments = append(segments, segment)
+	}
+
+	price, err := floatAt(record, mapping.Price)
+
+	if err != nil {
+		return domain.Flight{}, false, &fieldError{field: "price", reason: reasonNotANumber, err: err}
+	}
+
+	currency, err := stringAt(record, mapping.Currency)
+
+	if err != nil {
+		return domain.Flight{}, false, &fieldError{field: "currency", reason: reasonNotAString, err: err}
+	}
+
+	firstSegment := segments[0]
+	lastSegment := segments[len(segments)-1]
+
+	return domain.Flight{
+		Reference:     reference,
+		FlightNumber:  firstSegment.FlightNumber,
+		From:          firstSegment.From,
+		To:            lastSegment.To,
+		DepartureTime: firstSegment.DepartureTime,
+		ArrivalTime:   lastSegment.ArrivalTime,
+		Price:         price,
+		Currency:      currency,
+		Segments:      segments,
+	}, true, nil
+}
+
+func mapSegment(segmentNode any, mapping config.FieldMappingConfig) (domain.Segment, *fieldError) {
+	flightNumber, err := stringAt(segmentNode, mapping.FlightNumber)
+
+	if err != nil {
+		return domain.Segment{}, &fieldError{field: "flight_number", reason: reasonNotAString, err: err}
+	}
+
+	departureAirport, err := stringAt(segmentNode, mapping.From)
+
+	if err != nil {
+		return domain.Segment{}, &fieldError{field: "from", reason: reasonNotAString, err: err}
+	}
+
+	arrivalAirport, err := stringAt(segmentNode, mapping.To)
+
+	if err != nil {
+		return domain.Segment{}, &fieldError{field: "to", reason: reasonNotAString, err: err}
+	}
+
+	departureTime, err := timeAt(segmentNode, mapping.DepartureTime)
+
+	if err != nil {
+		return domain.Segment{}, &fieldError{field: "departure_time", reason: reasonNotATime, err: err}
+	}
+
+	arrivalTime, err := timeAt(segmentNode, mapping.ArrivalTime)
+
+	if err != nil {
+		return domain.Segment{}, &fieldError{field: "arrival_time", reason: reasonNotATime, err: err}
+	}
+
+	return domain.Segment{
+		FlightNumber:  flightNumber,
+		From:          departureAirport,
+		To:            arrivalAirport,
+		DepartureTime: departureTime,
+		ArrivalTime:   arrivalTime,
+	}, nil
+}
+
+func stringAt(node any, pointer string) (string, error) {
+	value, err := resolvePointer(node, pointer)
+
+	if err != nil {
+		return "", fmt.Errorf("flight field: %w", err)
+	}
+
+	switch typedValue := value.(type) {
+	case string:
+		return typedValue, nil
+	case json.Number:
+		return typedValue.String(), nil
+	default:
+		return "", fmt.Errorf("flight field %q: not a string", pointer)
+	}
+}
+
+func floatAt(node any, pointer string) (float64, error) {
+	value, err := resolvePointer(node, pointer)
+
+	if err != nil {
+		return 0, fmt.Errorf("flight field: %w", err)
+	}
+
+	switch typedValue := value.(type) {
+	case json.Number:
+		return typedValue.Float64()
+	case string:
+		number, err := strconv.ParseFloat(typedValue, 64)
+
+		if err != nil {
+			return 0, fmt.Errorf("flight bad number %q: %w", typedValue, err)
+		}
+
+		return number, nil
+	default:
+		return 0, fmt.Errorf("flight field %q: not a number", pointer)
+	}
+}
+
+func timeAt(node any, pointer string) (time.Time, error) {
+	value, err := stringAt(node, pointer)
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	parsedTime, err := time.Parse(time.RFC3339, value)
+
+	if err != nil {
+		return time.Time{}, fmt.Errorf("flight bad %s %q: %w", strings.TrimPrefix(pointer, "/"), value, err)
+	}
+
+	return parsedTime, nil
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// validatePointer checks the RFC 6901 syntax: empty, or "/"-prefixed tokens where "~" is
+// only used as "~0" or "~1".
+func validatePointer(pointer string) error {
+	if pointer == "" {
+		return nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return fmt.Errorf("json pointer %q must start with /", pointer)
+	}
+
+	for index := 0; index < len(pointer); index++ {
+		if pointer[index] != '~' {
+			continue
+		}
+
+		if index+1 == len(pointer) || (pointer[index+1] != '0' && pointer[index+1] != '1') {
+			return fmt.Errorf("json pointer %q: invalid escape", pointer)
+		}
+	}
+
+	return nil
+}
+
+func resolvePointer(document any, pointer string) (any, error) {
+	if pointer == "" {
+		return document, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("json pointer %q must start with /", pointer)
+	}
+
+	current := document
+
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+
+		switch node := current.(type) {
+		case map[string]any:
+			value, isPresent := node[token]
+
+			if !isPresent {
+				return nil, fmt.Errorf("json pointer %q: missing key %q", pointer, token)
+			}
+
+			current = value
+		case []any:
+			index, err := strconv.Atoi(token)
+
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, fmt.Errorf("json pointer %q: bad index %q", pointer, token)
+			}
+
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("json pointer %q: cannot traverse %q", pointer, token)
+		}
+	}
+
+	return current, nil
+}
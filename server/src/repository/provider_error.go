@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+)
+
+type ErrorCategory string
+
+const (
+	ErrorCategoryTimeout     ErrorCategory = "timeout"
+	ErrorCategoryTransport   ErrorCategory = "transport"
+	ErrorCategoryBadStatus   ErrorCategory = "bad_status"
+	ErrorCategoryDecode      ErrorCategory = "decode"
+	ErrorCategoryCircuitOpen ErrorCategory = "circuit_open"
+)
+
+var (
+	ErrTimeout     = errors.New("provider timeout")
+	ErrUnreachable = errors.New("provider unreachable")
+	ErrBadStatus   = errors.New("unexpected provider status")
+	ErrDecode      = errors.New("malformed provider payload")
+	ErrCircuitOpen = errors.New("circuit breaker is open")
+)
+
+var categorySentinels = map[ErrorCategory]error{
+	ErrorCategoryTimeout:     ErrTimeout,
+	ErrorCategoryTransport:   ErrUnreachable,
+	ErrorCategoryBadStatus:   ErrBadStatus,
+	ErrorCategoryDecode:      ErrDecode,
+	ErrorCategoryCircuitOpen: ErrCircuitOpen,
+}
+
+// ProviderError describes a failed provider call. Error only names the provider and the
+// category; the URL, the upstream body and the cause are kept for Detail.
+type ProviderError struct {
+	Provider   string
+	URL        string
+	StatusCode int
+	Body       string
+	Category   ErrorCategory
+	Err        error
+}
+
+func (providerError *ProviderError) Error() string {
+	message := "provider " + providerError.Provider + ": " + providerError.Category.Message()
+
+	if providerError.StatusCode != 0 {
+		message += ": status " + strconv.Itoa(providerError.StatusCode)
+	}
+
+	return message
+}
+
+// Detail is meant for server logs only.
+func (providerError *ProviderError) Detail() string {
+	detail := providerError.Error()
+
+	if providerError.URL != "" {
+		detail += " (GET " + providerError.URL + ")"
+	}
+
+	if providerError.Body != "" {
+		detail += ": body " + strconv.Quote(providerError.Body)
+	}
+
+	if providerError.Err != nil {
+		detail += ": " + providerError.Err.Error()
+	}
+
+	return detail
+}
+
+func (category ErrorCategory) Message() string {
+	if sentinel, isKnown := categorySentinels[category]; isKnown {
+		return sentinel.Error()
+	}
+
+	if category == "" {
+		return "provider error"
+	}
+
+	return string(category)
+}
+
+func (providerError *ProviderError) Unwrap() error {
+	return providerError.Err
+}
+
+func (providerError *ProviderError) Is(target error) bool {
+	sentinel, isKnown := categorySentinels[providerError.Category]
+
+	return isKnown && target == sentinel
+}
+
+func (provider httpProvider) providerError(category ErrorCategory, err error) *ProviderError {
+	return &ProviderError{Provider: provider.name, URL: provider.url, Category: category, Err: err}
+}
+
+func (provider httpProvider) decodeError(err error) *ProviderError {
+	return provider.providerError(ErrorCategoryDecode, err)
+}
+
+func isTimeout(err error) bool {
+	var netError net.Error
+
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netError) && netError.Timeout())
+}
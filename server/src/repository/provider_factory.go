@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Orden14/flight-aggregator/src/config"
+)
+
+func NewProviderRepository(providerConfig config.ProviderConfig, retryPolicy RetryPolicy) (FlightRepositoryInterface, error) {
+	switch providerConfig.Type {
+	case config.ProviderTypeServer1:
+		return NewServer1FlightRepository(providerConfig, retryPolicy), nil
+	case config.ProviderTypeServer2:
+		return NewServer2FlightRepository(providerConfig, retryPolicy), nil
+	case config.ProviderTypeJSON:
+		return NewJSONFlightRepository(providerConfig, retryPolicy)
+	default:
+		return nil, fmt.Errorf("provider %s: unknown type %q", providerConfig.Name, providerConfig.Type)
+	}
+}
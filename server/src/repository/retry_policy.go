@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/config"
+)
+
+type RetryPolicy struct {
+	MaxAttempts          int
+	BaseDelay            time.Duration
+	MaxDelay             time.Duration
+	Jitter               float64
+	RetryableStatusCodes []int
+}
+
+func NewRetryPolicy(retryConfig config.RetryConfig) RetryPolicy {
+	retryPolicy := RetryPolicy{
+		MaxAttempts:          retryConfig.MaxAttempts,
+		BaseDelay:            retryConfig.BaseDelay,
+		MaxDelay:             retryConfig.MaxDelay,
+		Jitter:               retryConfig.Jitter,
+		RetryableStatusCodes: retryConfig.RetryableStatusCodes,
+	}
+
+	if retryPolicy.MaxAttempts <= 0 {
+		retryPolicy.MaxAttempts = 1
+	}
+
+	if retryPolicy.MaxDelay < retryPolicy.BaseDelay {
+		retryPolicy.MaxDelay = retryPolicy.BaseDelay
+	}
+
+	if retryPolicy.Jitter < 0 {
+		retryPolicy.Jitter = 0
+	} else if retryPolicy.Jitter > 1 {
+		retryPolicy.Jitter = 1
+	}
+
+	return retryPolicy
+}
+
+func (retryPolicy RetryPolicy) isRetryableStatus(statusCode int) bool {
+	if len(retryPolicy.RetryableStatusCodes) == 0 {
+		return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+	}
+
+	return slices.Contains(retryPolicy.RetryableStatusCodes, statusCode)
+}
+
+func (retryPolicy RetryPolicy) backoff(attempt int) time.Duration {
+	delay := retryPolicy.BaseDelay
+
+	for i := 1; i < attempt && delay < retryPolicy.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > retryPolicy.MaxDelay {
+		delay = retryPolicy.MaxDelay
+	}
+
+	if retryPolicy.Jitter > 0 && delay > 0 {
+		delay -= time.Duration(rand.Float64() * retryPolicy.Jitter * float64(delay))
+	}
+
+	return delay
+}
+
+func retryAfter(response *http.Response) (time.Duration, bool) {
+	headerValue := response.Header.Get("Retry-After")
+
+	if headerValue == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(headerValue); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if retryDate, err := http.ParseTime(headerValue); err == nil {
+		return max(time.Until(retryDate), 0), true
+	}
+
+	return 0, false
+}
+
+// getWithRetry stops early when the next wait would exceed the context deadline.
+func getWithRetry(ctx context.Context, client *http.Client, url string, header http.Header, retryPolicy RetryPolicy) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for key, values := range header {
+			request.Header[key] = values
+		}
+
+		response, err := client.Do(request)
+
+		var retryAfterDelay time.Duration
+		var hasRetryAfter bool
+
+		if response != nil {
+			retryAfterDelay, hasRetryAfter = retryAfter(response)
+		}
+
+		// A 429 without Retry-After gives no hint that waiting helps, so it is not retried.
+		if err == nil && (!retryPolicy.isRetryableStatus(response.StatusCode) || (response.StatusCode == http.StatusTooManyRequests && !hasRetryAfter)) {
+			return response, nil
+		}
+
+		if attempt >= retryPolicy.MaxAttempts || ctx.Err() != nil {
+			return response, err
+		}
+
+		delay := retryPolicy.backoff(attempt)
+
+		if hasRetryAfter {
+			delay = retryAfterDelay
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			return response, err
+		}
+
+		if response != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 1<<14))
+			response.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
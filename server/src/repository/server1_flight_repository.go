@@ -3,9 +3,6 @@ package repository
 import (
 	"context"
 	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
 	"time"
 
 	"github.com/Orden14/flight-aggregator/src/config"
@@ -14,61 +11,62 @@ import (
 )
 
 type Server1FlightRepository struct {
-	baseURL string
-	client  *http.Client
+	httpProvider
 }
 
-func NewServer1FlightRepository(config config.JSONServerConfig) *Server1FlightRepository {
+func NewServer1FlightRepository(providerConfig config.ProviderConfig, retryPolicy RetryPolicy) *Server1FlightRepository {
 	return &Server1FlightRepository{
-		baseURL: config.BaseURL(),
-		client:  &http.Client{Timeout: 0},
+		httpProvider: newHTTPProvider(providerConfig, "/flights", retryPolicy),
 	}
 }
 
 func (flightRepository *Server1FlightRepository) Fetch(ctx context.Context) ([]domain.Flight, error) {
-	url := fmt.Sprintf("%s/flights", flightRepository.baseURL)
+	report, err := flightRepository.FetchReport(ctx)
 
-	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-
-	if err != nil {
-		return nil, fmt.Errorf("flight build request: %w", err)
-	}
+	return report.Flights, err
+}
 
-	response, err := flightRepository.client.Do(request)
+func (flightRepository *Server1FlightRepository) FetchReport(ctx context.Context) (FetchReport, error) {
+	var rawItems []json.RawMessage
 
-	if err != nil {
-		return nil, fmt.Errorf("flight GET %s: %w", url, err)
+	if err := flightRepository.fetchJSON(ctx, &rawItems); err != nil {
+		return FetchReport{}, err
 	}
 
-	defer response.Body.Close()
+	recordCollector := flightRepository.newRecordCollector(len(rawItems))
 
-	if response.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(io.LimitReader(response.Body, 1<<14))
-
-		return nil, fmt.Errorf("flight status %d: %s", response.StatusCode, string(body))
-	}
+	for _, rawItem := range rawItems {
+		var flight model.Server1FlightItem
 
-	var flightItems []model.Server1FlightItem
+		if err := json.Unmarshal(rawItem, &flight); err != nil {
+			if err := recordCollector.skip("", err); err != nil {
+				return FetchReport{}, err
+			}
 
-	if err := json.NewDecoder(response.Body).Decode(&flightItems); err != nil {
-		return nil, fmt.Errorf("flight decode array: %w", err)
-	}
+			continue
+		}
 
-	flightsResponse := make([]domain.Flight, 0, len(flightItems))
-	for _, flight := range flightItems {
 		departureTime, err := time.Parse(time.RFC3339, flight.DepartureTime)
 
 		if err != nil {
-			return nil, fmt.Errorf("flight bad departureTime %q: %w", flight.DepartureTime, err)
+			if err := recordCollector.skip(flight.BookingID, &fieldError{field: "departureTime", reason: reasonNotATime, err: err}); err != nil {
+				return FetchReport{}, err
+			}
+
+			continue
 		}
 
 		arrivalTime, err := time.Parse(time.RFC3339, flight.ArrivalTime)
 
 		if err != nil {
-			return nil, fmt.Errorf("flight bad arrivalTime %q: %w", flight.ArrivalTime, err)
+			if err := recordCollector.skip(flight.BookingID, &fieldError{field: "arrivalTime", reason: reasonNotATime, err: err}); err != nil {
+				return FetchReport{}, err
+			}
+
+			continue
 		}
 
-		flightsResponse = append(flightsResponse, domain.Flight{
+		recordCollector.add(domain.Flight{
 			Reference:     flight.BookingID,
 			FlightNumber:  flight.FlightNumber,
 			From:          flight.DepartureAirport,
@@ -77,8 +75,17 @@ func (flightRepository *Server1FlightRepository) Fetch(ctx context.Context) ([]d
 			ArrivalTime:   arrivalTime,
 			Price:         flight.Price,
 			Currency:      flight.Currency,
-		})
+			Segments: []domain.Segment{
+				{
+					FlightNumber:  flight.FlightNumber,
+					From:          flight.DepartureAirport,
+					To:            flight.ArrivalAirport,
+					DepartureTime: departureTime,
+					ArrivalTime:   arrivalTime,
+				},
+			},
+		}, rawItem)
 	}
 
-	return flightsResponse, nil
+	return recordCollector.report, nil
 }
@@ -4,8 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"time"
 
 	"github.com/Orden14/flight-aggregator/src/config"
@@ -14,79 +12,98 @@ import (
 )
 
 type Server2FlightRepository struct {
-	baseURL string
-	client  *http.Client
+	httpProvider
 }
 
-func NewServer2FlightRepository(config config.JSONServerConfig) *Server2FlightRepository {
+func NewServer2FlightRepository(providerConfig config.ProviderConfig, retryPolicy RetryPolicy) *Server2FlightRepository {
 	return &Server2FlightRepository{
-		baseURL: config.BaseURL(),
-		client:  &http.Client{Timeout: 0},
+		httpProvider: newHTTPProvider(providerConfig, "/flight_to_book", retryPolicy),
 	}
 }
 
 func (flightRepository *Server2FlightRepository) Fetch(ctx context.Context) ([]domain.Flight, error) {
-	url := fmt.Sprintf("%s/flight_to_book", flightRepository.baseURL)
+	report, err := flightRepository.FetchReport(ctx)
 
-	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-
-	if err != nil {
-		return nil, fmt.Errorf("flight build request: %w", err)
-	}
+	return report.Flights, err
+}
 
-	response, err := flightRepository.client.Do(request)
+func (flightRepository *Server2FlightRepository) FetchReport(ctx context.Context) (FetchReport, error) {
+	var rawItems []json.RawMessage
 
-	if err != nil {
-		return nil, fmt.Errorf("flight GET %s: %w", url, err)
+	if err := flightRepository.fetchJSON(ctx, &rawItems); err != nil {
+		return FetchReport{}, err
 	}
 
-	defer response.Body.Close()
+	recordCollector := flightRepository.newRecordCollector(len(rawItems))
 
-	if response.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(io.LimitReader(response.Body, 1<<14))
+	for _, rawItem := range rawItems {
+		var flight model.Server2FlightItem
 
-		return nil, fmt.Errorf("flight status %d: %s", response.StatusCode, string(body))
-	}
+		if err := json.Unmarshal(rawItem, &flight); err != nil {
+			if err := recordCollector.skip("", err); err != nil {
+				return FetchReport{}, err
+			}
 
-	var flightItems []model.Server2FlightItem
+			continue
+		}
 
-	if err := json.NewDecoder(response.Body).Decode(&flightItems); err != nil {
-		return nil, fmt.Errorf("flight decode array: %w", err)
-	}
+		if len(flight.Segments) == 0 {
+			continue
+		}
 
-	flightsResponse := make([]domain.Flight, 0, len(flightItems))
+		segments, err := parseServer2Segments(flight)
+
+		if err != nil {
+			if err := recordCollector.skip(flight.Reference, err); err != nil {
+				return FetchReport{}, err
+			}
 
-	for _, flight := range flightItems {
-		if len(flight.Segments) == 0 {
 			continue
 		}
 
-		firstSegment := flight.Segments[0].Flight
-		lastSegment := flight.Segments[len(flight.Segments)-1].Flight
+		firstSegment := segments[0]
+		lastSegment := segments[len(segments)-1]
+
+		recordCollector.add(domain.Flight{
+			Reference:     flight.Reference,
+			FlightNumber:  firstSegment.FlightNumber,
+			From:          firstSegment.From,
+			To:            lastSegment.To,
+			DepartureTime: firstSegment.DepartureTime,
+			ArrivalTime:   lastSegment.ArrivalTime,
+			Price:         flight.Total.Amount,
+			Currency:      flight.Total.Currency,
+			Segments:      segments,
+		}, rawItem)
+	}
+
+	return recordCollector.report, nil
+}
+
+func parseServer2Segments(flight model.Server2FlightItem) ([]domain.Segment, error) {
+	segments := make([]domain.Segment, 0, len(flight.Segments))
 
-		departureTime, err := time.Parse(time.RFC3339, firstSegment.Depart)
+	for index, segment := range flight.Segments {
+		departureTime, err := time.Parse(time.RFC3339, segment.Flight.Depart)
 
 		if err != nil {
-			return nil, fmt.Errorf("flight bad depart %q: %w", firstSegment.Depart, err)
+			return nil, &fieldError{field: fmt.Sprintf("segments[%d].flight.depart", index), reason: reasonNotATime, err: err}
 		}
 
-		arrivalTime, err := time.Parse(time.RFC3339, lastSegment.Arrive)
+		arrivalTime, err := time.Parse(time.RFC3339, segment.Flight.Arrive)
 
 		if err != nil {
-			return nil, fmt.Errorf("flight bad arrive %q: %w", lastSegment.Arrive, err)
+			return nil, &fieldError{field: fmt.Sprintf("segments[%d].flight.arrive", index), reason: reasonNotATime, err: err}
 		}
 
-		flightsResponse = append(flightsResponse, domain.Flight{
-			Reference:     flight.Reference,
-			FlightNumber:  firstSegment.Number,
-			From:          firstSegment.From,
-			To:            lastSegment.To,
+		segments = append(segments, domain.Segment{
+			FlightNumber:  segment.Flight.Number,
+			From:          segment.Flight.From,
+			To:            segment.Flight.To,
 			DepartureTime: departureTime,
 			ArrivalTime:   arrivalTime,
-			Price:         flight.Total.Amount,
-			Currency:      flight.Total.Currency,
 		})
 	}
 
-	return flightsResponse, nil
+	return segments, nil
 }
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"github.com/Orden14/flight-aggregator/src/util/airport"
+	"github.com/Orden14/flight-aggregator/src/util/filter"
+)
+
+func (query FlightQuery) departureAirports() []string {
+	return expandAirport(query.DepartureAirport, query.DepartureRadiusKm)
+}
+
+func (query FlightQuery) arrivalAirports() []string {
+	return expandAirport(query.ArrivalAirport, query.ArrivalRadiusKm)
+}
+
+func expandAirport(code string, radiusKm float64) []string {
+	if radiusKm > 0 {
+		return airport.Near(code, radiusKm)
+	}
+
+	return airport.Expand(code)
+}
+
+func (query FlightQuery) predicates() []filter.Predicate {
+	var predicates []filter.Predicate
+
+	if query.DepartureAirport != "" {
+		predicates = append(predicates, filter.DepartureAirport(query.departureAirports()...))
+	}
+
+	if query.ArrivalAirport != "" {
+		predicates = append(predicates, filter.ArrivalAirport(query.arrivalAirports()...))
+	}
+
+	if query.DirectOnly {
+		predicates = append(predicates, filter.DirectOnly())
+	}
+
+	if query.MaxStops != nil {
+		predicates = append(predicates, filter.MaxStops(*query.MaxStops))
+	}
+
+	if query.MinLayover > 0 || query.MaxLayover > 0 {
+		predicates = append(predicates, filter.LayoverBetween(query.MinLayover, query.MaxLayover))
+	}
+
+	if query.MaxDuration > 0 {
+		predicates = append(predicates, filter.MaxDuration(query.MaxDuration))
+	}
+
+	if query.DepartureDateFrom != "" || query.DepartureDateTo != "" {
+		predicates = append(predicates, filter.DepartureDateBetween(query.DepartureDateFrom, query.DepartureDateTo))
+	}
+
+	if query.DepartureWindow != nil {
+		predicates = append(predicates, filter.DepartureWithin(*query.DepartureWindow))
+	}
+
+	if query.ArrivalWindow != nil {
+		predicates = append(predicates, filter.ArrivalWithin(*query.ArrivalWindow))
+	}
+
+	if query.MinPrice != nil {
+		predicates = append(predicates, filter.MinPrice(*query.MinPrice))
+	}
+
+	if query.MaxPrice != nil {
+		predicates = append(predicates, filter.MaxPrice(*query.MaxPrice))
+	}
+
+	if len(query.Airlines) > 0 {
+		predicates = append(predicates, filter.Airline(query.Airlines...))
+	}
+
+	if len(query.ExcludedAirlines) > 0 {
+		predicates = append(predicates, filter.ExcludeAirline(query.ExcludedAirlines...))
+	}
+
+	if query.FlightNumber != "" {
+		predicates = append(predicates, filter.FlightNumber(query.FlightNumber))
+	}
+
+	return predicates
+}
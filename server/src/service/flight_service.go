@@ -1,94 +1,544 @@
 package service
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
 	"errors"
-	"sync"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/Orden14/flight-aggregator/src/domain"
 	"github.com/Orden14/flight-aggregator/src/repository"
-	"github.com/Orden14/flight-aggregator/src/util/errtools"
+	"github.com/Orden14/flight-aggregator/src/util/filter"
+	"github.com/Orden14/flight-aggregator/src/util/route"
 	"github.com/Orden14/flight-aggregator/src/util/sorter"
 )
 
+type FetchMode string
+
+const (
+	FetchModeDegraded FetchMode = "degraded"
+	FetchModeStrict   FetchMode = "strict"
+)
+
+const (
+	ProviderStatusOK    = "ok"
+	ProviderStatusStale = "stale"
+	ProviderStatusError = "error"
+)
+
+type FlightQuery struct {
+	DepartureAirport string
+	ArrivalAirport   string
+	MaxStops         *int
+	DirectOnly       bool
+	MinLayover       time.Duration
+	MaxLayover       time.Duration
+	MaxDuration      time.Duration
+	// Interline adds virtual interline itineraries from DepartureAirport to ArrivalAirport,
+	// changing ticket after MinConnection to MaxConnection.
+	Interline     bool
+	MinConnection time.Duration
+	MaxConnection time.Duration
+	// DepartureDateFrom and DepartureDateTo are YYYY-MM-DD dates in the departure airport's time zone.
+	DepartureDateFrom string
+	DepartureDateTo   string
+	DepartureWindow   *filter.TimeWindow
+	ArrivalWindow     *filter.TimeWindow
+	MinPrice          *float64
+	MaxPrice          *float64
+	Airlines          []string
+	ExcludedAirlines  []string
+	FlightNumber      string
+	SortBy            sorter.SortBy
+	SortOrder         sorter.Order
+	Mode              FetchMode
+	// DepartureAirport and ArrivalAirport are airport or city codes. A radius widens
+	// them to the airports within that many kilometres.
+	DepartureRadiusKm float64
+	ArrivalRadiusKm   float64
+}
+
+type ProviderStatus struct {
+	Name           string                   `json:"name"`
+	Status         string                   `json:"status"`
+	LatencyMs      int64                    `json:"latency_ms"`
+	Error          string                   `json:"error,omitempty"`
+	ErrorCategory  string                   `json:"error_category,omitempty"`
+	UpstreamStatus int                      `json:"upstream_status,omitempty"`
+	SkippedRecords int                      `json:"skipped_records,omitempty"`
+	RecordIssues   []repository.RecordIssue `json:"record_issues,omitempty"`
+	Circuit        string                   `json:"circuit,omitempty"`
+	Cache          string                   `json:"cache,omitempty"`
+	Stale          bool                     `json:"stale,omitempty"`
+	AgeSeconds     int64                    `json:"age_seconds,omitempty"`
+	TTLSeconds     int64                    `json:"-"`
+}
+
+var (
+	ErrNoRepositories = errors.New("no repositories configured")
+	ErrFlightNotFound = errors.New("flight not found")
+)
+
+// ProviderFailureError names the providers whose failure made a search fail.
+type ProviderFailureError struct {
+	Providers []string
+	Err       error
+}
+
+func (providerFailureError *ProviderFailureError) Error() string {
+	return providerFailureError.Err.Error()
+}
+
+func (providerFailureError *ProviderFailureError) Unwrap() error {
+	return providerFailureError.Err
+}
+
+type FlightSearchResult struct {
+	Flights   []domain.Flight
+	Providers []ProviderStatus
+	// Generation identifies the cached provider data the flights were built from.
+	// It stays the same until a provider's cache entry is refreshed.
+	Generation uint64
+}
+
+// FlightOffer is one provider's offer. Reference names the ticket of an interline flight.
+type FlightOffer struct {
+	Reference string          `json:"reference,omitempty"`
+	Provider  string          `json:"provider"`
+	Price     float64         `json:"price"`
+	Currency  string          `json:"currency"`
+	Raw       json.RawMessage `json:"raw,omitempty"`
+}
+
+type FlightDetail struct {
+	Flight    domain.Flight
+	Offers    []FlightOffer
+	Providers []ProviderStatus
+}
+
+// ProviderBatch holds one provider's flights, already filtered, sorted and enriched,
+// delivered as soon as that provider answers.
+type ProviderBatch struct {
+	Provider ProviderStatus
+	Flights  []domain.Flight
+}
+
 type FlightService interface {
-	GetFlights(ctx context.Context, departureAirport string, arrivalAirport string, sortBy sorter.SortBy, sortOrder sorter.Order) ([]domain.Flight, error)
+	GetFlights(ctx context.Context, query FlightQuery) (*FlightSearchResult, error)
+	StreamFlights(ctx context.Context, query FlightQuery, onBatch func(ProviderBatch)) (*FlightSearchResult, error)
+	GetFlight(ctx context.Context, reference string, mode FetchMode) (*FlightDetail, error)
+	SearchRoundTrips(ctx context.Context, query RoundTripQuery) (*ItinerarySearchResult, error)
+	SearchMultiCity(ctx context.Context, query MultiCityQuery) (*ItinerarySearchResult, error)
+	PriceCalendar(ctx context.Context, query CalendarQuery) (*PriceCalendar, error)
+	Reload(options FlightServiceOptions, repositories ...repository.FlightRepositoryInterface)
+}
+
+type FlightServiceOptions struct {
+	RepositoryTimeout         time.Duration
+	CacheTTL                  time.Duration
+	CacheStaleWhileRevalidate time.Duration
+	CacheStaleIfError         time.Duration
 }
 
 type flightService struct {
+	state atomic.Pointer[flightServiceState]
+}
+
+type flightServiceState struct {
 	repositories      []repository.FlightRepositoryInterface
 	repositoryTimeout time.Duration
+	cache             *providerCache
+}
+
+type providerResult struct {
+	flights    []domain.Flight
+	raw        []json.RawMessage
+	status     ProviderStatus
+	err        error
+	generation uint64
+}
+
+func ParseFetchMode(inputValue string) (FetchMode, bool) {
+	switch strings.ToLower(inputValue) {
+	case "strict":
+		return FetchModeStrict, true
+	case "degraded":
+		return FetchModeDegraded, true
+	default:
+		return FetchModeDegraded, false
+	}
+}
+
+func NormalizeFetchMode(inputValue string) FetchMode {
+	fetchMode, _ := ParseFetchMode(inputValue)
+
+	return fetchMode
 }
 
 func NewFlightService(timeout time.Duration, repositories ...repository.FlightRepositoryInterface) FlightService {
-	if timeout <= 0 {
-		timeout = 10 * time.Second
+	return NewFlightServiceWithOptions(FlightServiceOptions{RepositoryTimeout: timeout * time.Second}, repositories...)
+}
+
+func NewFlightServiceWithOptions(options FlightServiceOptions, repositories ...repository.FlightRepositoryInterface) FlightService {
+	flightService := &flightService{}
+	flightService.Reload(options, repositories...)
+
+	return flightService
+}
+
+// Reload swaps the repositories and options atomically: searches already running keep
+// the state they started with, and cached provider data survives when caching stays enabled.
+func (flightService *flightService) Reload(options FlightServiceOptions, repositories ...repository.FlightRepositoryInterface) {
+	if options.RepositoryTimeout <= 0 {
+		options.RepositoryTimeout = 10 * time.Second
 	}
 
-	return &flightService{
+	state := &flightServiceState{
 		repositories:      repositories,
-		repositoryTimeout: timeout * time.Second,
+		repositoryTimeout: options.RepositoryTimeout,
+	}
+
+	if options.CacheTTL > 0 {
+		if previousState := flightService.state.Load(); previousState != nil && previousState.cache != nil {
+			state.cache = previousState.cache
+			state.cache.configure(options.CacheTTL, options.CacheStaleWhileRevalidate, options.CacheStaleIfError, repositories)
+		} else {
+			state.cache = newProviderCache(options.CacheTTL, options.CacheStaleWhileRevalidate, options.CacheStaleIfError, repositories)
+		}
 	}
+
+	flightService.state.Store(state)
+}
+
+func (flightService *flightService) GetFlights(ctx context.Context, query FlightQuery) (*FlightSearchResult, error) {
+	return flightService.StreamFlights(ctx, query, nil)
 }
 
-func (flightService *flightService) GetFlights(ctx context.Context, departureAirport string, arrivalAirport string, sortBy sorter.SortBy, sortOrder sorter.Order) ([]domain.Flight, error) {
-	if len(flightService.repositories) == 0 {
-		return nil, errors.New("no repositories configured")
+// StreamFlights calls onBatch from the calling goroutine each time a provider answers,
+// then returns the merged result exactly as GetFlights does.
+func (flightService *flightService) StreamFlights(ctx context.Context, query FlightQuery, onBatch func(ProviderBatch)) (*FlightSearchResult, error) {
+	state := flightService.state.Load()
+
+	if len(state.repositories) == 0 {
+		return nil, ErrNoRepositories
+	}
+
+	var onResult func(providerResult)
+
+	if onBatch != nil {
+		onResult = func(result providerResult) {
+			onBatch(ProviderBatch{Provider: result.status, Flights: flightService.prepareFlights(result.flights, query)})
+		}
 	}
 
-	flights, err := flightService.fetchAll(ctx)
+	results := state.fetchAll(ctx, onResult)
+
+	flights, err := flightService.mergeResults(results, query.Mode)
+
 	if err != nil {
 		return nil, err
 	}
 
+	filteredFlights := flightService.prepareFlights(flights, query)
+
+	providers := make([]ProviderStatus, 0, len(results))
+
+	for _, result := range results {
+		providers = append(providers, result.status)
+	}
+
+	return &FlightSearchResult{
+		Flights:    filteredFlights,
+		Providers:  providers,
+		Generation: resultGeneration(results),
+	}, nil
+}
+
+func resultGeneration(results []providerResult) uint64 {
+	hash := fnv.New64a()
+
+	for _, result := range results {
+		fmt.Fprintf(hash, "%s:%d;", result.status.Name, result.generation)
+	}
+
+	return hash.Sum64()
+}
+
+// reportGeneration stands in for the cache generation when caching is off, so cursors
+// still go stale once a provider returns different flights.
+func reportGeneration(report repository.FetchReport) uint64 {
+	hash := fnv.New64a()
+	_ = json.NewEncoder(hash).Encode(report.Flights)
+
+	return hash.Sum64()
+}
+
+func (flightService *flightService) prepareFlights(flights []domain.Flight, query FlightQuery) []domain.Flight {
 	flights = flightService.dedupeFlights(flights)
-	filteredFlights := flightService.filterFlights(flights, departureAirport, arrivalAirport)
-	sorter.SortFlights(filteredFlights, sortBy, sortOrder)
+
+	if query.Interline {
+		flights = append(flights, route.Connect(flights, query.departureAirports(), query.arrivalAirports(), route.Constraints{
+			MinConnection: query.MinConnection,
+			MaxConnection: query.MaxConnection,
+			MaxStops:      query.MaxStops,
+			MaxDuration:   query.MaxDuration,
+		})...)
+	}
+
+	filteredFlights := flightService.filterFlights(flights, query)
+	sorter.SortFlights(filteredFlights, query.SortBy, query.SortOrder)
 	flightService.enrichFlights(&filteredFlights)
 
-	return filteredFlights, nil
+	return filteredFlights
+}
+
+// aggregateFlights returns every provider's flights deduplicated but neither filtered nor
+// sorted, for searches that combine flights rather than list them.
+func (flightService *flightService) aggregateFlights(ctx context.Context, mode FetchMode) (*FlightSearchResult, error) {
+	state := flightService.state.Load()
+
+	if len(state.repositories) == 0 {
+		return nil, ErrNoRepositories
+	}
+
+	results := state.fetchAll(ctx, nil)
+
+	flights, err := flightService.mergeResults(results, mode)
+
+	if err != nil {
+		return nil, err
+	}
+
+	providers := make([]ProviderStatus, 0, len(results))
+
+	for _, result := range results {
+		providers = append(providers, result.status)
+	}
+
+	return &FlightSearchResult{
+		Flights:    flightService.dedupeFlights(flights),
+		Providers:  providers,
+		Generation: resultGeneration(results),
+	}, nil
 }
 
-func (flightService *flightService) fetchAll(ctx context.Context) ([]domain.Flight, error) {
-	var waitGroup sync.WaitGroup
+// GetFlight looks a reference up across every provider. The flight is the one a search
+// would return, the offers list every provider listing with its raw record.
+func (flightService *flightService) GetFlight(ctx context.Context, reference string, mode FetchMode) (*FlightDetail, error) {
+	state := flightService.state.Load()
+
+	if len(state.repositories) == 0 {
+		return nil, ErrNoRepositories
+	}
+
+	results := state.fetchAll(ctx, nil)
+
+	if _, err := flightService.mergeResults(results, mode); err != nil {
+		return nil, err
+	}
+
+	detail := &FlightDetail{Providers: make([]ProviderStatus, 0, len(results))}
+
+	// A virtual interline reference such as A1+B2 is rebuilt from its tickets.
+	tickets := strings.Split(reference, "+")
+	ticketFlights := make([][]domain.Flight, len(tickets))
+
+	for _, result := range results {
+		detail.Providers = append(detail.Providers, result.status)
 
-	results := make(chan []domain.Flight, len(flightService.repositories))
-	errs := make(chan error, len(flightService.repositories))
+		for index, flight := range result.flights {
+			ticketIndex := slices.Index(tickets, flight.Reference)
 
-	for _, flightRepository := range flightService.repositories {
-		waitGroup.Add(1)
+			if ticketIndex < 0 {
+				continue
+			}
+
+			offer := FlightOffer{Provider: result.status.Name, Price: flight.Price, Currency: flight.Currency}
+
+			if len(tickets) > 1 {
+				offer.Reference = flight.Reference
+			}
+
+			if index < len(result.raw) {
+				offer.Raw = result.raw[index]
+			}
 
-		go func(r repository.FlightRepositoryInterface) {
-			defer waitGroup.Done()
+			detail.Offers = append(detail.Offers, offer)
+			ticketFlights[ticketIndex] = append(ticketFlights[ticketIndex], flight)
+		}
+	}
+
+	flights := make([]domain.Flight, 0, len(tickets))
+
+	for _, matchingFlights := range ticketFlights {
+		if len(matchingFlights) == 0 {
+			return nil, ErrFlightNotFound
+		}
 
-			requestContext, cancel := context.WithTimeout(ctx, flightService.repositoryTimeout)
+		flights = append(flights, flightService.dedupeFlights(matchingFlights)[0])
+	}
+
+	if len(tickets) > 1 {
+		itinerary, isChained := route.Join(flights)
+
+		if !isChained {
+			return nil, ErrFlightNotFound
+		}
+
+		flights = []domain.Flight{itinerary}
+	}
+
+	flightService.enrichFlights(&flights)
+	detail.Flight = flights[0]
+
+	slices.SortStableFunc(detail.Offers, func(offerA FlightOffer, offerB FlightOffer) int {
+		return cmp.Or(
+			cmp.Compare(slices.Index(tickets, offerA.Reference), slices.Index(tickets, offerB.Reference)),
+			cmp.Compare(offerA.Price, offerB.Price),
+			strings.Compare(offerA.Provider, offerB.Provider),
+		)
+	})
+
+	return detail, nil
+}
+
+// fetchAll queries every provider concurrently; onResult, when set, sees each result in
+// completion order.
+func (state *flightServiceState) fetchAll(ctx context.Context, onResult func(providerResult)) []providerResult {
+	results := make([]providerResult, len(state.repositories))
+	completed := make(chan int, len(state.repositories))
+
+	for index, flightRepository := range state.repositories {
+		go func(index int, r repository.FlightRepositoryInterface) {
+			defer func() { completed <- index }()
+
+			requestContext, cancel := context.WithTimeout(ctx, state.repositoryTimeout)
 			defer cancel()
 
-			flights, err := r.Fetch(requestContext)
+			startedAt := time.Now()
 
-			if err != nil {
-				errs <- err
+			if state.cache == nil {
+				report, err := repository.FetchWithReport(requestContext, r)
+				results[index] = newProviderResult(r, report, err, time.Since(startedAt))
+				results[index].generation = reportGeneration(report)
 
 				return
 			}
 
-			results <- flights
-		}(flightRepository)
+			cached := state.cache.fetch(requestContext, r, state.repositoryTimeout)
+			results[index] = newProviderResult(r, cached.report, cached.err, time.Since(startedAt))
+			results[index].status.applyCache(cached)
+			results[index].generation = cached.generation
+		}(index, flightRepository)
+	}
+
+	for range state.repositories {
+		index := <-completed
+
+		if onResult != nil {
+			onResult(results[index])
+		}
+	}
+
+	return results
+}
+
+func newProviderResult(flightRepository repository.FlightRepositoryInterface, report repository.FetchReport, err error, latency time.Duration) providerResult {
+	status := ProviderStatus{
+		Name:           flightRepository.Name(),
+		Status:         ProviderStatusOK,
+		LatencyMs:      latency.Milliseconds(),
+		SkippedRecords: report.SkippedRecords,
+		RecordIssues:   report.Issues,
 	}
 
-	waitGroup.Wait()
-	close(results)
-	close(errs)
+	if circuitStateReporter, ok := flightRepository.(repository.CircuitStateReporter); ok {
+		status.Circuit = string(circuitStateReporter.State())
+	}
+
+	if err != nil {
+		status.Status = ProviderStatusError
+		status.setError(err)
+		logProviderError(err)
+	}
+
+	return providerResult{flights: report.Flights, raw: report.Raw, status: status, err: err}
+}
+
+func (providerStatus *ProviderStatus) applyCache(cached cacheResult) {
+	providerStatus.Cache = string(cached.status)
+	providerStatus.AgeSeconds = int64(cached.age.Seconds())
+	providerStatus.TTLSeconds = int64(cached.ttl.Seconds())
+
+	if cached.status != CacheStatusStale {
+		return
+	}
+
+	providerStatus.Stale = true
+
+	if cached.fetchErr != nil {
+		providerStatus.Status = ProviderStatusStale
+		providerStatus.setError(cached.fetchErr)
+	}
+}
 
-	if firstErr := errtools.GetFirstError(errs); firstErr != nil {
-		return nil, firstErr
+// setError only exposes the category and the upstream status; the raw error may hold
+// upstream URLs or bodies and is logged instead.
+func (providerStatus *ProviderStatus) setError(err error) {
+	var providerError *repository.ProviderError
+
+	switch {
+	case errors.As(err, &providerError):
+		providerStatus.ErrorCategory = string(providerError.Category)
+		providerStatus.UpstreamStatus = providerError.StatusCode
+	case errors.Is(err, context.DeadlineExceeded):
+		providerStatus.ErrorCategory = string(repository.ErrorCategoryTimeout)
 	}
 
+	providerStatus.Error = repository.ErrorCategory(providerStatus.ErrorCategory).Message()
+}
+
+func logProviderError(err error) {
+	var providerError *repository.ProviderError
+
+	if errors.As(err, &providerError) {
+		log.Println("provider fetch failed:", providerError.Detail())
+
+		return
+	}
+
+	log.Println("provider fetch failed:", err)
+}
+
+func (flightService *flightService) mergeResults(results []providerResult, mode FetchMode) ([]domain.Flight, error) {
 	var flights []domain.Flight
+	var failedProviders []string
+	var errs []error
 
-	for flight := range results {
-		flights = append(flights, flight...)
+	for _, result := range results {
+		if result.err != nil {
+			if mode == FetchModeStrict {
+				return nil, &ProviderFailureError{Providers: []string{result.status.Name}, Err: result.err}
+			}
+
+			failedProviders = append(failedProviders, result.status.Name)
+			errs = append(errs, result.err)
+
+			continue
+		}
+
+		flights = append(flights, result.flights...)
+	}
+
+	if len(errs) == len(results) {
+		return nil, &ProviderFailureError{Providers: failedProviders, Err: errors.Join(errs...)}
 	}
 
 	return flights, nil
@@ -123,33 +573,14 @@ func (flightService *flightService) dedupeFlights(flights []domain.Flight) []dom
 	return dedupedFlights
 }
 
-func (flightService *flightService) filterFlights(flights []domain.Flight, departureAirport string, arrivalAirport string) []domain.Flight {
-	if departureAirport == "" && arrivalAirport == "" {
-		filteredFlights := make([]domain.Flight, len(flights))
-		copy(filteredFlights, flights)
-
-		return filteredFlights
-	}
-
-	filteredFlights := make([]domain.Flight, 0, len(flights))
-
-	for _, flight := range flights {
-		if departureAirport != "" && flight.From != departureAirport {
-			continue
-		}
-
-		if arrivalAirport != "" && flight.To != arrivalAirport {
-			continue
-		}
-
-		filteredFlights = append(filteredFlights, flight)
-	}
-
-	return filteredFlights
+func (flightService *flightService) filterFlights(flights []domain.Flight, query FlightQuery) []domain.Flight {
+	return filter.Apply(flights, query.predicates()...)
 }
 
 func (flightService *flightService) enrichFlights(flights *[]domain.Flight) {
 	for i := range *flights {
 		(*flights)[i].TravelTimeMinutes = int((*flights)[i].Duration().Minutes())
+		(*flights)[i].Stops = (*flights)[i].StopCount()
+		(*flights)[i].Layovers = (*flights)[i].ConnectionLayovers()
 	}
 }
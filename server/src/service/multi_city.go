@@ -0,0 +1,195 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/Orden14/flight-aggregator/src/domain"
+	"github.com/Orden14/flight-aggregator/src/util/sorter"
+)
+
+const (
+	defaultMultiCityLimit = 10
+	maxCombinationNodes   = 100_000
+)
+
+// MultiCityQuery chains one flight per leg, in order. Objective is the total minimised,
+// price or travel time, and Limit the number of combinations kept.
+type MultiCityQuery struct {
+	Legs      []FlightQuery
+	Objective sorter.SortBy
+	Limit     int
+	Mode      FetchMode
+}
+
+func (flightService *flightService) SearchMultiCity(ctx context.Context, query MultiCityQuery) (*ItinerarySearchResult, error) {
+	result, err := flightService.aggregateFlights(ctx, query.Mode)
+
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([][]domain.Flight, 0, len(query.Legs))
+
+	for _, leg := range query.Legs {
+		legFlights := flightService.filterFlights(result.Flights, leg)
+		flightService.enrichFlights(&legFlights)
+		candidates = append(candidates, legFlights)
+	}
+
+	limit := query.Limit
+
+	if limit <= 0 {
+		limit = defaultMultiCityLimit
+	}
+
+	itineraries, isTruncated := bestCombinations(candidates, query.Objective, limit)
+	sorter.SortItineraries(itineraries, query.Objective, sorter.OrderAsc)
+
+	return &ItinerarySearchResult{
+		Itineraries: itineraries,
+		Providers:   result.Providers,
+		Generation:  result.Generation,
+		Truncated:   isTruncated,
+	}, nil
+}
+
+type combination struct {
+	cost    float64
+	flights []domain.Flight
+}
+
+// combinationSearch is a branch and bound over the legs, capped at maxCombinationNodes.
+type combinationSearch struct {
+	candidates     [][]domain.Flight
+	objective      sorter.SortBy
+	limit          int
+	remainingBound []float64
+	best           []combination
+	nodes          int
+	isTruncated    bool
+}
+
+// bestCombinations keeps the combinations that chain in time, in a single currency.
+func bestCombinations(candidates [][]domain.Flight, objective sorter.SortBy, limit int) ([]domain.Itinerary, bool) {
+	combinationSearch := &combinationSearch{
+		candidates:     candidates,
+		objective:      objective,
+		limit:          limit,
+		remainingBound: make([]float64, len(candidates)+1),
+	}
+
+	pruneUnreachable(candidates)
+
+	for legIndex := len(candidates) - 1; legIndex >= 0; legIndex-- {
+		if len(candidates[legIndex]) == 0 {
+			return []domain.Itinerary{}, false
+		}
+
+		slices.SortStableFunc(candidates[legIndex], func(flightA domain.Flight, flightB domain.Flight) int {
+			return sorter.CompareKeys(sorter.SortKey(flightA, objective), flightA.Reference, sorter.SortKey(flightB, objective), flightB.Reference, sorter.OrderAsc)
+		})
+
+		combinationSearch.remainingBound[legIndex] = combinationSearch.remainingBound[legIndex+1] + sorter.SortKey(candidates[legIndex][0], objective)
+	}
+
+	combinationSearch.extend(nil, 0)
+
+	itineraries := make([]domain.Itinerary, 0, len(combinationSearch.best))
+
+	for _, bestCombination := range combinationSearch.best {
+		itineraries = append(itineraries, domain.NewItinerary(bestCombination.flights...))
+	}
+
+	return itineraries, combinationSearch.isTruncated
+}
+
+// pruneUnreachable drops the flights leaving before any flight of the previous leg lands,
+// and those landing after every flight of the next leg has left.
+func pruneUnreachable(candidates [][]domain.Flight) {
+	for legIndex := 1; legIndex < len(candidates); legIndex++ {
+		if len(candidates[legIndex-1]) == 0 {
+			continue
+		}
+
+		earliestArrival := slices.MinFunc(candidates[legIndex-1], func(flightA domain.Flight, flightB domain.Flight) int {
+			return flightA.ArrivalTime.Compare(flightB.ArrivalTime)
+		}).ArrivalTime
+
+		candidates[legIndex] = slices.DeleteFunc(candidates[legIndex], func(flight domain.Flight) bool {
+			return flight.DepartureTime.Before(earliestArrival)
+		})
+	}
+
+	for legIndex := len(candidates) - 2; legIndex >= 0; legIndex-- {
+		if len(candidates[legIndex+1]) == 0 {
+			continue
+		}
+
+		latestDeparture := slices.MaxFunc(candidates[legIndex+1], func(flightA domain.Flight, flightB domain.Flight) int {
+			return flightA.DepartureTime.Compare(flightB.DepartureTime)
+		}).DepartureTime
+
+		candidates[legIndex] = slices.DeleteFunc(candidates[legIndex], func(flight domain.Flight) bool {
+			return flight.ArrivalTime.After(latestDeparture)
+		})
+	}
+}
+
+func (combinationSearch *combinationSearch) extend(flights []domain.Flight, cost float64) {
+	combinationSearch.nodes++
+
+	if combinationSearch.nodes > maxCombinationNodes {
+		combinationSearch.isTruncated = true
+
+		return
+	}
+
+	legIndex := len(flights)
+
+	if legIndex == len(combinationSearch.candidates) {
+		combinationSearch.keep(combination{cost: cost, flights: slices.Clone(flights)})
+
+		return
+	}
+
+	for _, flight := range combinationSearch.candidates[legIndex] {
+		flightCost := cost + sorter.SortKey(flight, combinationSearch.objective)
+
+		// Candidates are sorted, so no later flight of this leg can do better.
+		if !combinationSearch.canImprove(flightCost + combinationSearch.remainingBound[legIndex+1]) {
+			return
+		}
+
+		if legIndex > 0 {
+			previousFlight := flights[legIndex-1]
+
+			if flight.DepartureTime.Before(previousFlight.ArrivalTime) || flight.Currency != previousFlight.Currency {
+				continue
+			}
+		}
+
+		combinationSearch.extend(append(flights, flight), flightCost)
+
+		if combinationSearch.isTruncated {
+			return
+		}
+	}
+}
+
+func (combinationSearch *combinationSearch) canImprove(cost float64) bool {
+	return len(combinationSearch.best) < combinationSearch.limit || cost <= combinationSearch.best[len(combinationSearch.best)-1].cost
+}
+
+func (combinationSearch *combinationSearch) keep(newCombination combination) {
+	index, _ := slices.BinarySearchFunc(combinationSearch.best, newCombination.cost, func(bestCombination combination, cost float64) int {
+		return cmp.Compare(bestCombination.cost, cost)
+	})
+
+	combinationSearch.best = slices.Insert(combinationSearch.best, index, newCombination)
+
+	if len(combinationSearch.best) > combinationSearch.limit {
+		combinationSearch.best = combinationSearch.best[:combinationSearch.limit]
+	}
+}
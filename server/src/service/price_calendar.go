@@ -0,0 +1,133 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"iter"
+	"slices"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/domain"
+	"github.com/Orden14/flight-aggregator/src/util/filter"
+)
+
+// CalendarQuery prices every departure day of Month, a YYYY-MM month. With RoundTrip
+// the return constraints also apply and the cheapest itinerary per pair of days is given.
+type CalendarQuery struct {
+	RoundTripQuery
+	Month     string
+	RoundTrip bool
+}
+
+// CalendarDay has a nil price when no flight leaves that day, and one entry per currency.
+type CalendarDay struct {
+	Date         string   `json:"date"`
+	Price        *float64 `json:"price"`
+	Currency     string   `json:"currency,omitempty"`
+	Reference    string   `json:"reference,omitempty"`
+	FlightsCount int      `json:"flightsCount"`
+}
+
+type CalendarPair struct {
+	DepartureDate string  `json:"departureDate"`
+	ReturnDate    string  `json:"returnDate"`
+	Price         float64 `json:"price"`
+	Currency      string  `json:"currency"`
+	Reference     string  `json:"reference"`
+}
+
+type PriceCalendar struct {
+	Days       []CalendarDay
+	Pairs      []CalendarPair
+	Providers  []ProviderStatus
+	Generation uint64
+}
+
+// PriceCalendar works on a single fetch of every provider, the same dataset GetFlights
+// searches, rather than one search per day.
+func (flightService *flightService) PriceCalendar(ctx context.Context, query CalendarQuery) (*PriceCalendar, error) {
+	firstDay, err := time.Parse("2006-01", query.Month)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := flightService.aggregateFlights(ctx, query.Mode)
+
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &PriceCalendar{Providers: result.Providers, Generation: result.Generation}
+	dateIndexes := make(map[string]int)
+	dayIndexes := make(map[[2]string]int)
+
+	for day := firstDay; day.Month() == firstDay.Month(); day = day.AddDate(0, 0, 1) {
+		dateIndexes[day.Format(time.DateOnly)] = len(calendar.Days)
+		calendar.Days = append(calendar.Days, CalendarDay{Date: day.Format(time.DateOnly)})
+	}
+
+	query.Outbound.DepartureDateFrom = calendar.Days[0].Date
+	query.Outbound.DepartureDateTo = calendar.Days[len(calendar.Days)-1].Date
+
+	outboundFlights := flightService.filterFlights(result.Flights, query.Outbound)
+
+	for _, flight := range outboundFlights {
+		date := filter.DepartureDate(flight)
+		index, isKnown := dayIndexes[[2]string{date, flight.Currency}]
+
+		if !isKnown {
+			index = dateIndexes[date]
+
+			if calendar.Days[index].FlightsCount > 0 {
+				index = len(calendar.Days)
+				calendar.Days = append(calendar.Days, CalendarDay{Date: date})
+			}
+
+			dayIndexes[[2]string{date, flight.Currency}] = index
+		}
+
+		calendarDay := &calendar.Days[index]
+		calendarDay.FlightsCount++
+
+		if calendarDay.Price == nil || flight.Price < *calendarDay.Price {
+			calendarDay.Price = &flight.Price
+			calendarDay.Currency = flight.Currency
+			calendarDay.Reference = flight.Reference
+		}
+	}
+
+	slices.SortStableFunc(calendar.Days, func(dayA CalendarDay, dayB CalendarDay) int {
+		return cmp.Or(cmp.Compare(dayA.Date, dayB.Date), cmp.Compare(dayA.Currency, dayB.Currency))
+	})
+
+	if query.RoundTrip {
+		returnFlights := flightService.filterFlights(result.Flights, query.Return)
+		calendar.Pairs = cheapestPairs(pairRoundTrips(outboundFlights, returnFlights, query.RoundTripQuery))
+	}
+
+	return calendar, nil
+}
+
+func cheapestPairs(itineraries iter.Seq[domain.Itinerary]) []CalendarPair {
+	pairIndexes := make(map[[3]string]int)
+	pairs := make([]CalendarPair, 0)
+
+	for itinerary := range itineraries {
+		key := [3]string{filter.DepartureDate(itinerary.Flights[0]), filter.DepartureDate(itinerary.Flights[1]), itinerary.Currency}
+		pair := CalendarPair{DepartureDate: key[0], ReturnDate: key[1], Price: itinerary.Price, Currency: itinerary.Currency, Reference: itinerary.Reference}
+
+		if index, isKnown := pairIndexes[key]; !isKnown {
+			pairIndexes[key] = len(pairs)
+			pairs = append(pairs, pair)
+		} else if itinerary.Price < pairs[index].Price {
+			pairs[index] = pair
+		}
+	}
+
+	slices.SortFunc(pairs, func(pairA CalendarPair, pairB CalendarPair) int {
+		return cmp.Or(cmp.Compare(pairA.DepartureDate, pairB.DepartureDate), cmp.Compare(pairA.ReturnDate, pairB.ReturnDate), cmp.Compare(pairA.Currency, pairB.Currency))
+	})
+
+	return pairs
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/repository"
+)
+
+type CacheStatus string
+
+const (
+	CacheStatusHit       CacheStatus = "hit"
+	CacheStatusMiss      CacheStatus = "miss"
+	CacheStatusCollapsed CacheStatus = "collapsed"
+	CacheStatusStale     CacheStatus = "stale"
+)
+
+type cacheEntry struct {
+	report     repository.FetchReport
+	storedAt   time.Time
+	generation uint64
+}
+
+type cacheResult struct {
+	report repository.FetchReport
+	status CacheStatus
+	age    time.Duration
+	ttl    time.Duration
+	err    error
+	// fetchErr is the upstream error hidden by a stale-if-error answer.
+	fetchErr error
+	// generation identifies the stored entry the flights come from.
+	generation uint64
+}
+
+type inflightFetch struct {
+	done       chan struct{}
+	report     repository.FetchReport
+	err        error
+	generation uint64
+}
+
+type providerCache struct {
+	ttl                  time.Duration
+	staleWhileRevalidate time.Duration
+	staleIfError         time.Duration
+
+	// Entries are keyed by repository rather than by name: a reload builds a new repository
+	// for a provider whose configuration changed, and its old data must not be served.
+	// Only the repositories of the current configuration get their fetches stored.
+	mutex        sync.Mutex
+	repositories map[repository.FlightRepositoryInterface]bool
+	entries      map[repository.FlightRepositoryInterface]cacheEntry
+	inflight     map[repository.FlightRepositoryInterface]*inflightFetch
+}
+
+// lastGeneration is shared by every cache and seeded from the clock, so a new cache or a
+// restarted process never hands out a generation an older cursor was bound to.
+var lastGeneration = func() *atomic.Uint64 {
+	generation := &atomic.Uint64{}
+	generation.Store(uint64(time.Now().UnixNano()))
+
+	return generation
+}()
+
+func newProviderCache(ttl time.Duration, staleWhileRevalidate time.Duration, staleIfError time.Duration, repositories []repository.FlightRepositoryInterface) *providerCache {
+	providerCache := &providerCache{
+		entries:  make(map[repository.FlightRepositoryInterface]cacheEntry),
+		inflight: make(map[repository.FlightRepositoryInterface]*inflightFetch),
+	}
+
+	providerCache.configure(ttl, staleWhileRevalidate, staleIfError, repositories)
+
+	return providerCache
+}
+
+func (providerCache *providerCache) configure(ttl time.Duration, staleWhileRevalidate time.Duration, staleIfError time.Duration, repositories []repository.FlightRepositoryInterface) {
+	providerCache.mutex.Lock()
+	defer providerCache.mutex.Unlock()
+
+	providerCache.ttl = ttl
+	providerCache.staleWhileRevalidate = staleWhileRevalidate
+	providerCache.staleIfError = staleIfError
+
+	liveRepositories := make(map[repository.FlightRepositoryInterface]bool, len(repositories))
+	retainedEntries := make(map[repository.FlightRepositoryInterface]cacheEntry, len(repositories))
+
+	for _, flightRepository := range repositories {
+		liveRepositories[flightRepository] = true
+
+		if entry, isCached := providerCache.entries[flightRepository]; isCached {
+			retainedEntries[flightRepository] = entry
+		}
+	}
+
+	providerCache.repositories = liveRepositories
+	providerCache.entries = retainedEntries
+}
+
+func (providerCache *providerCache) fetch(ctx context.Context, flightRepository repository.FlightRepositoryInterface, timeout time.Duration) cacheResult {
+	providerCache.mutex.Lock()
+
+	ttl := providerCache.ttl
+	staleWhileRevalidate := providerCache.staleWhileRevalidate
+	staleIfError := providerCache.staleIfError
+
+	entry, isCached := providerCache.entries[flightRepository]
+	age := time.Since(entry.storedAt)
+
+	if isCached && age < ttl {
+		providerCache.mutex.Unlock()
+
+		return cacheResult{report: entry.report, generation: entry.generation, status: CacheStatusHit, age: age, ttl: ttl - age}
+	}
+
+	call, isLeader := providerCache.startFetch(ctx, flightRepository, timeout)
+
+	providerCache.mutex.Unlock()
+
+	if isCached && age < ttl+staleWhileRevalidate {
+		return cacheResult{report: entry.report, generation: entry.generation, status: CacheStatusStale, age: age, ttl: ttl - age}
+	}
+
+	cacheStatus := CacheStatusCollapsed
+
+	if isLeader {
+		cacheStatus = CacheStatusMiss
+	}
+
+	select {
+	case <-call.done:
+		if call.err != nil && isCached && age < ttl+staleIfError {
+			return cacheResult{report: entry.report, generation: entry.generation, status: CacheStatusStale, age: age, ttl: ttl - age, fetchErr: call.err}
+		}
+
+		return cacheResult{report: call.report, generation: call.generation, status: cacheStatus, ttl: ttl, err: call.err}
+	case <-ctx.Done():
+		if isCached && age < ttl+staleIfError {
+			return cacheResult{report: entry.report, generation: entry.generation, status: CacheStatusStale, age: age, ttl: ttl - age, fetchErr: ctx.Err()}
+		}
+
+		return cacheResult{status: cacheStatus, err: ctx.Err()}
+	}
+}
+
+// startFetch must be called with the mutex held. The upstream call is detached from
+// the caller's cancellation so that collapsed and background callers share its result.
+func (providerCache *providerCache) startFetch(ctx context.Context, flightRepository repository.FlightRepositoryInterface, timeout time.Duration) (*inflightFetch, bool) {
+	if call, isInflight := providerCache.inflight[flightRepository]; isInflight {
+		return call, false
+	}
+
+	call := &inflightFetch{done: make(chan struct{})}
+	providerCache.inflight[flightRepository] = call
+
+	go func() {
+		fetchContext, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+		defer cancel()
+
+		call.report, call.err = repository.FetchWithReport(fetchContext, flightRepository)
+
+		providerCache.mutex.Lock()
+		delete(providerCache.inflight, flightRepository)
+
+		if call.err == nil {
+			call.generation = lastGeneration.Add(1)
+
+			if providerCache.repositories[flightRepository] {
+				providerCache.entries[flightRepository] = cacheEntry{report: call.report, storedAt: time.Now(), generation: call.generation}
+			}
+		}
+
+		providerCache.mutex.Unlock()
+
+		close(call.done)
+	}()
+
+	return call, true
+}
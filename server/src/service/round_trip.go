@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/domain"
+	"github.com/Orden14/flight-aggregator/src/util/airport"
+	"github.com/Orden14/flight-aggregator/src/util/sorter"
+)
+
+const maxRoundTripItineraries = 1000
+
+// RoundTripQuery pairs the flights matching Outbound with those matching Return. Prices
+// bound the itinerary total, the stay is the time between landing and flying back.
+type RoundTripQuery struct {
+	Outbound  FlightQuery
+	Return    FlightQuery
+	MinStay   time.Duration
+	MaxStay   time.Duration
+	MinPrice  *float64
+	MaxPrice  *float64
+	SortBy    sorter.SortBy
+	SortOrder sorter.Order
+	Mode      FetchMode
+}
+
+type ItinerarySearchResult struct {
+	Itineraries []domain.Itinerary
+	Providers   []ProviderStatus
+	Generation  uint64
+	// Truncated is set when the search stopped before exploring every combination.
+	Truncated bool
+}
+
+// SearchRoundTrips combines flights from any providers, so that the outbound and the
+// return of an itinerary may be sold by different ones.
+func (flightService *flightService) SearchRoundTrips(ctx context.Context, query RoundTripQuery) (*ItinerarySearchResult, error) {
+	result, err := flightService.aggregateFlights(ctx, query.Mode)
+
+	if err != nil {
+		return nil, err
+	}
+
+	outboundFlights := flightService.filterFlights(result.Flights, query.Outbound)
+	returnFlights := flightService.filterFlights(result.Flights, query.Return)
+	flightService.enrichFlights(&outboundFlights)
+	flightService.enrichFlights(&returnFlights)
+
+	itineraries, isTruncated := bestItineraries(pairRoundTrips(outboundFlights, returnFlights, query), query.SortBy, query.SortOrder)
+
+	return &ItinerarySearchResult{
+		Itineraries: itineraries,
+		Providers:   result.Providers,
+		Generation:  result.Generation,
+		Truncated:   isTruncated,
+	}, nil
+}
+
+// bestItineraries keeps the first maxRoundTripItineraries itineraries in sort order,
+// without holding every pair in memory.
+func bestItineraries(itineraries iter.Seq[domain.Itinerary], sortBy sorter.SortBy, sortOrder sorter.Order) ([]domain.Itinerary, bool) {
+	best := make([]domain.Itinerary, 0)
+	isTruncated := false
+
+	for itinerary := range itineraries {
+		best = append(best, itinerary)
+
+		if len(best) == 2*maxRoundTripItineraries {
+			sorter.SortItineraries(best, sortBy, sortOrder)
+			best = best[:maxRoundTripItineraries]
+			isTruncated = true
+		}
+	}
+
+	sorter.SortItineraries(best, sortBy, sortOrder)
+
+	if len(best) > maxRoundTripItineraries {
+		best = best[:maxRoundTripItineraries]
+		isTruncated = true
+	}
+
+	return best, isTruncated
+}
+
+// pairRoundTrips pairs flights by city, so a trip may return from another Paris airport.
+func pairRoundTrips(outboundFlights []domain.Flight, returnFlights []domain.Flight, query RoundTripQuery) iter.Seq[domain.Itinerary] {
+	return func(yield func(domain.Itinerary) bool) {
+		returnFlightsByRoute := make(map[string][]domain.Flight)
+
+		for _, returnFlight := range returnFlights {
+			route := airport.CityCode(returnFlight.From) + "-" + airport.CityCode(returnFlight.To)
+			returnFlightsByRoute[route] = append(returnFlightsByRoute[route], returnFlight)
+		}
+
+		for _, outboundFlight := range outboundFlights {
+			for _, returnFlight := range returnFlightsByRoute[airport.CityCode(outboundFlight.To)+"-"+airport.CityCode(outboundFlight.From)] {
+				stay := returnFlight.DepartureTime.Sub(outboundFlight.ArrivalTime)
+
+				if stay < max(query.MinStay, 0) || (query.MaxStay > 0 && stay > query.MaxStay) || returnFlight.Currency != outboundFlight.Currency {
+					continue
+				}
+
+				itinerary := domain.NewItinerary(outboundFlight, returnFlight)
+
+				if (query.MinPrice != nil && itinerary.Price < *query.MinPrice) || (query.MaxPrice != nil && itinerary.Price > *query.MaxPrice) {
+					continue
+				}
+
+				if !yield(itinerary) {
+					return
+				}
+			}
+		}
+	}
+}
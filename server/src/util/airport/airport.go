@@ -0,0 +1,156 @@
+package airport
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata"
+)
+
+//go:embed airports.csv
+var airportsCSV string
+
+type Airport struct {
+	Code      string
+	City      string
+	Country   string
+	Latitude  float64
+	Longitude float64
+	Timezone  string
+}
+
+const earthRadiusKm = 6371.0
+
+// airports is parsed once from the embedded dataset. The dataset ships with the binary,
+// so a malformed row is a build mistake and panics.
+var airports = sync.OnceValue(func() map[string]Airport {
+	records, err := csv.NewReader(strings.NewReader(airportsCSV)).ReadAll()
+
+	if err != nil {
+		panic("airport: invalid embedded dataset: " + err.Error())
+	}
+
+	airportsByCode := make(map[string]Airport, len(records)-1)
+
+	for _, record := range records[1:] {
+		latitude, latitudeErr := strconv.ParseFloat(record[3], 64)
+		longitude, longitudeErr := strconv.ParseFloat(record[4], 64)
+
+		if latitudeErr != nil || longitudeErr != nil {
+			panic("airport: invalid coordinates for " + record[0])
+		}
+
+		if _, err := time.LoadLocation(record[5]); err != nil {
+			panic("airport: invalid time zone for " + record[0])
+		}
+
+		airportsByCode[record[0]] = Airport{
+			Code:      record[0],
+			City:      record[1],
+			Country:   record[2],
+			Latitude:  latitude,
+			Longitude: longitude,
+			Timezone:  record[5],
+		}
+	}
+
+	return airportsByCode
+})
+
+func Lookup(code string) (Airport, bool) {
+	airport, isKnown := airports()[code]
+
+	return airport, isKnown
+}
+
+// Known reports whether the code is an airport or a city of the dataset.
+func Known(code string) bool {
+	for _, airport := range airports() {
+		if airport.Code == code || airport.City == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CityCode returns the city an airport serves, or the code itself when it is unknown.
+func CityCode(code string) string {
+	if airport, isKnown := Lookup(code); isKnown {
+		return airport.City
+	}
+
+	return code
+}
+
+// Expand turns a city code (PAR) into the airports serving it (BVA, CDG, ORY), including
+// airports named after their city (DXB also matches DWC). Any other code only matches itself.
+func Expand(code string) []string {
+	var codes []string
+
+	for _, airport := range airports() {
+		if airport.City == code {
+			codes = append(codes, airport.Code)
+		}
+	}
+
+	if len(codes) == 0 {
+		return []string{code}
+	}
+
+	slices.Sort(codes)
+
+	return codes
+}
+
+// Near returns the airports within radiusKm of an airport or of any airport of a city,
+// great-circle distances included.
+func Near(code string, radiusKm float64) []string {
+	var centers []Airport
+
+	for _, centerCode := range Expand(code) {
+		if center, isKnown := Lookup(centerCode); isKnown {
+			centers = append(centers, center)
+		}
+	}
+
+	if len(centers) == 0 {
+		return []string{code}
+	}
+
+	var codes []string
+
+	for _, airport := range airports() {
+		for _, center := range centers {
+			if DistanceKm(center, airport) <= radiusKm {
+				codes = append(codes, airport.Code)
+
+				break
+			}
+		}
+	}
+
+	slices.Sort(codes)
+
+	return codes
+}
+
+// DistanceKm is the haversine great-circle distance between two airports.
+func DistanceKm(airportA Airport, airportB Airport) float64 {
+	latitudeA, latitudeB := radians(airportA.Latitude), radians(airportB.Latitude)
+	latitudeDelta := latitudeB - latitudeA
+	longitudeDelta := radians(airportB.Longitude - airportA.Longitude)
+
+	haversine := math.Pow(math.Sin(latitudeDelta/2), 2) + math.Cos(latitudeA)*math.Cos(latitudeB)*math.Pow(math.Sin(longitudeDelta/2), 2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(haversine))
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
@@ -0,0 +1,29 @@
+package airport
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+var locations sync.Map
+
+// Location returns the local time zone of an airport. Airports missing from the dataset
+// fall back to UTC and are logged once.
+func Location(code string) *time.Location {
+	if location, isLoaded := locations.Load(code); isLoaded {
+		return location.(*time.Location)
+	}
+
+	location := time.UTC
+
+	if airport, isKnown := Lookup(code); isKnown {
+		location, _ = time.LoadLocation(airport.Timezone)
+	} else {
+		log.Println("airport: unknown airport", code, "local dates use UTC")
+	}
+
+	actual, _ := locations.LoadOrStore(code, location)
+
+	return actual.(*time.Location)
+}
@@ -1,11 +0,0 @@
-package errtools
-
-func GetFirstError(errs <-chan error) error {
-	for err := range errs {
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
@@ -0,0 +1,173 @@
+package filter
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Orden14/flight-aggregator/src/domain"
+	"github.com/Orden14/flight-aggregator/src/util/airport"
+)
+
+type Predicate func(flight domain.Flight) bool
+
+func Apply(flights []domain.Flight, predicates ...Predicate) []domain.Flight {
+	matches := All(predicates...)
+	filteredFlights := make([]domain.Flight, 0, len(flights))
+
+	for _, flight := range flights {
+		if matches(flight) {
+			filteredFlights = append(filteredFlights, flight)
+		}
+	}
+
+	return filteredFlights
+}
+
+func All(predicates ...Predicate) Predicate {
+	return func(flight domain.Flight) bool {
+		for _, predicate := range predicates {
+			if !predicate(flight) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+func DepartureAirport(codes ...string) Predicate {
+	return func(flight domain.Flight) bool { return slices.Contains(codes, flight.From) }
+}
+
+func ArrivalAirport(codes ...string) Predicate {
+	return func(flight domain.Flight) bool { return slices.Contains(codes, flight.To) }
+}
+
+func DirectOnly() Predicate {
+	return MaxStops(0)
+}
+
+func MaxStops(maxStops int) Predicate {
+	return func(flight domain.Flight) bool { return flight.StopCount() <= maxStops }
+}
+
+func MaxDuration(maxDuration time.Duration) Predicate {
+	return func(flight domain.Flight) bool { return flight.Duration() <= maxDuration }
+}
+
+// LayoverBetween checks every connection of the itinerary; a zero bound is ignored.
+func LayoverBetween(minLayover time.Duration, maxLayover time.Duration) Predicate {
+	return func(flight domain.Flight) bool {
+		for _, layover := range flight.ConnectionLayovers() {
+			layoverDuration := time.Duration(layover.DurationMinutes) * time.Minute
+
+			if minLayover > 0 && layoverDuration < minLayover {
+				return false
+			}
+
+			if maxLayover > 0 && layoverDuration > maxLayover {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// DepartureDate is the YYYY-MM-DD departure date in the departure airport's time zone.
+func DepartureDate(flight domain.Flight) string {
+	return flight.DepartureTime.In(airport.Location(flight.From)).Format(time.DateOnly)
+}
+
+// DepartureDateBetween compares departure dates as returned by DepartureDate; an empty bound is ignored.
+func DepartureDateBetween(from string, to string) Predicate {
+	return func(flight domain.Flight) bool {
+		departureDate := DepartureDate(flight)
+
+		return (from == "" || departureDate >= from) && (to == "" || departureDate <= to)
+	}
+}
+
+func DepartureWithin(timeWindow TimeWindow) Predicate {
+	return func(flight domain.Flight) bool {
+		return timeWindow.Contains(flight.DepartureTime.In(airport.Location(flight.From)))
+	}
+}
+
+func ArrivalWithin(timeWindow TimeWindow) Predicate {
+	return func(flight domain.Flight) bool {
+		return timeWindow.Contains(flight.ArrivalTime.In(airport.Location(flight.To)))
+	}
+}
+
+func MinPrice(minPrice float64) Predicate {
+	return func(flight domain.Flight) bool { return flight.Price >= minPrice }
+}
+
+func MaxPrice(maxPrice float64) Predicate {
+	return func(flight domain.Flight) bool { return flight.Price <= maxPrice }
+}
+
+// Airline matches the carrier that sells the itinerary, i.e. the one of its flight number.
+func Airline(carrierCodes ...string) Predicate {
+	return func(flight domain.Flight) bool {
+		return slices.Contains(carrierCodes, CarrierCode(flight.FlightNumber))
+	}
+}
+
+// ExcludeAirline rejects itineraries where any segment is flown by one of the carriers.
+func ExcludeAirline(carrierCodes ...string) Predicate {
+	return func(flight domain.Flight) bool {
+		if slices.Contains(carrierCodes, CarrierCode(flight.FlightNumber)) {
+			return false
+		}
+
+		for _, segment := range flight.Segments {
+			if slices.Contains(carrierCodes, CarrierCode(segment.FlightNumber)) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+func FlightNumber(flightNumber string) Predicate {
+	flightNumber = normalizeFlightNumber(flightNumber)
+
+	return func(flight domain.Flight) bool {
+		if normalizeFlightNumber(flight.FlightNumber) == flightNumber {
+			return true
+		}
+
+		for _, segment := range flight.Segments {
+			if normalizeFlightNumber(segment.FlightNumber) == flightNumber {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// CarrierCode returns the airline designator of a flight number: its two-character
+// IATA prefix (AF276 -> AF, U21234 -> U2), or three letters for ICAO numbers (AFR276 -> AFR).
+func CarrierCode(flightNumber string) string {
+	flightNumber = normalizeFlightNumber(flightNumber)
+
+	if len(flightNumber) < 3 {
+		return flightNumber
+	}
+
+	if unicode.IsLetter(rune(flightNumber[0])) && unicode.IsLetter(rune(flightNumber[1])) && unicode.IsLetter(rune(flightNumber[2])) {
+		return flightNumber[:3]
+	}
+
+	return flightNumber[:2]
+}
+
+func normalizeFlightNumber(flightNumber string) string {
+	return strings.ToUpper(strings.ReplaceAll(flightNumber, " ", ""))
+}
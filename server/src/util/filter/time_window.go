@@ -0,0 +1,21 @@
+package filter
+
+import "time"
+
+type TimeWindow struct {
+	From time.Duration
+	To   time.Duration
+}
+
+// Contains compares the wall-clock time of localTime with the window; a window whose
+// end is before its start wraps past midnight (e.g. 22:00-02:00).
+func (timeWindow TimeWindow) Contains(localTime time.Time) bool {
+	hour, minute, second := localTime.Clock()
+	sinceMidnight := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute + time.Duration(second)*time.Second
+
+	if timeWindow.From <= timeWindow.To {
+		return sinceMidnight >= timeWindow.From && sinceMidnight <= timeWindow.To
+	}
+
+	return sinceMidnight >= timeWindow.From || sinceMidnight <= timeWindow.To
+}
@@ -0,0 +1,104 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/Orden14/flight-aggregator/src/domain"
+	"github.com/Orden14/flight-aggregator/src/util/sorter"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor resumes a sorted snapshot by sort key. Generation detects a changed snapshot.
+type Cursor struct {
+	Generation  uint64        `json:"g"`
+	Fingerprint uint64        `json:"f"`
+	SortBy      sorter.SortBy `json:"s"`
+	Order       sorter.Order  `json:"o"`
+	Key         float64       `json:"k"`
+	Reference   string        `json:"r"`
+	Backward    bool          `json:"b,omitempty"`
+}
+
+func (cursor Cursor) Encode() string {
+	payload, _ := json.Marshal(cursor)
+
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+func DecodeCursor(encodedCursor string) (Cursor, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(encodedCursor)
+
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+
+	if err := json.Unmarshal(payload, &cursor); err != nil || cursor.Reference == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+type Page struct {
+	Flights []domain.Flight
+	// Start is the index of the first flight of the page in the full list.
+	Start   int
+	Total   int
+	HasPrev bool
+	HasNext bool
+}
+
+func AtOffset(flights []domain.Flight, offset int, limit int) Page {
+	start := min(offset, len(flights))
+
+	return newPage(flights, start, min(start+limit, len(flights)))
+}
+
+// AfterCursor returns the limit flights that follow the cursor, or that precede it for
+// a backward cursor. flights must be sorted by the cursor's sort key and order.
+func AfterCursor(flights []domain.Flight, cursor Cursor, limit int) Page {
+	boundary := len(flights)
+
+	for index, flight := range flights {
+		if sorter.CompareKeys(sorter.SortKey(flight, cursor.SortBy), flight.Reference, cursor.Key, cursor.Reference, cursor.Order) >= 0 {
+			boundary = index
+
+			break
+		}
+	}
+
+	if cursor.Backward {
+		return newPage(flights, max(boundary-limit, 0), boundary)
+	}
+
+	if boundary < len(flights) && flights[boundary].Reference == cursor.Reference {
+		boundary++
+	}
+
+	return newPage(flights, boundary, min(boundary+limit, len(flights)))
+}
+
+func newPage(flights []domain.Flight, start int, end int) Page {
+	return Page{
+		Flights: flights[start:end],
+		Start:   start,
+		Total:   len(flights),
+		HasPrev: start > 0,
+		HasNext: end < len(flights),
+	}
+}
+
+func CursorAt(flight domain.Flight, sortBy sorter.SortBy, order sorter.Order, backward bool) Cursor {
+	return Cursor{
+		SortBy:    sortBy,
+		Order:     order,
+		Key:       sorter.SortKey(flight, sortBy),
+		Reference: flight.Reference,
+		Backward:  backward,
+	}
+}
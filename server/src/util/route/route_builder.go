@@ -0,0 +1,171 @@
+package route
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/domain"
+)
+
+// DefaultMinConnection leaves time to collect bags and check in again between tickets.
+const DefaultMinConnection = 2 * time.Hour
+
+// DefaultMaxConnection keeps overnight connections but not multi-day stopovers.
+const DefaultMaxConnection = 24 * time.Hour
+
+// maxTickets bounds the search: a traveler rarely accepts more than two self-transfers.
+const maxTickets = 3
+
+// Constraints bound the connections between tickets only. A zero MaxConnection is unbounded.
+type Constraints struct {
+	MinConnection time.Duration
+	MaxConnection time.Duration
+	MaxStops      *int
+	MaxDuration   time.Duration
+}
+
+// Connect chains separately sold flights, in a single currency, into virtual interline itineraries.
+func Connect(flights []domain.Flight, origins []string, destinations []string, constraints Constraints) []domain.Flight {
+	flightsByOrigin := make(map[string][]domain.Flight)
+
+	for _, flight := range flights {
+		flightsByOrigin[flight.From] = append(flightsByOrigin[flight.From], flight)
+	}
+
+	routeBuilder := &routeBuilder{
+		flightsByOrigin: flightsByOrigin,
+		destinations:    destinations,
+		constraints:     constraints,
+		visited:         make(map[string]bool),
+	}
+
+	for _, origin := range origins {
+		routeBuilder.visited[origin] = true
+	}
+
+	for _, origin := range origins {
+		for _, flight := range flightsByOrigin[origin] {
+			if slices.Contains(destinations, flight.To) || routeBuilder.visited[flight.To] || !routeBuilder.fits([]domain.Flight{flight}) {
+				continue
+			}
+
+			routeBuilder.extend([]domain.Flight{flight})
+		}
+	}
+
+	return routeBuilder.itineraries
+}
+
+type routeBuilder struct {
+	flightsByOrigin map[string][]domain.Flight
+	destinations    []string
+	constraints     Constraints
+	visited         map[string]bool
+	itineraries     []domain.Flight
+}
+
+func (routeBuilder *routeBuilder) extend(path []domain.Flight) {
+	lastFlight := path[len(path)-1]
+	routeBuilder.visited[lastFlight.To] = true
+	defer delete(routeBuilder.visited, lastFlight.To)
+
+	for _, nextFlight := range routeBuilder.flightsByOrigin[lastFlight.To] {
+		if routeBuilder.visited[nextFlight.To] || nextFlight.Currency != lastFlight.Currency {
+			continue
+		}
+
+		connection := nextFlight.DepartureTime.Sub(lastFlight.ArrivalTime)
+
+		if connection < routeBuilder.constraints.MinConnection || (routeBuilder.constraints.MaxConnection > 0 && connection > routeBuilder.constraints.MaxConnection) {
+			continue
+		}
+
+		nextPath := append(path[:len(path):len(path)], nextFlight)
+
+		if !routeBuilder.fits(nextPath) {
+			continue
+		}
+
+		if slices.Contains(routeBuilder.destinations, nextFlight.To) {
+			routeBuilder.itineraries = append(routeBuilder.itineraries, combine(nextPath))
+		} else if len(nextPath) < maxTickets {
+			routeBuilder.extend(nextPath)
+		}
+	}
+}
+
+// fits prunes paths that already break the stop or duration limit, as extending them
+// can only make it worse.
+func (routeBuilder *routeBuilder) fits(path []domain.Flight) bool {
+	segmentCount := 0
+
+	for _, flight := range path {
+		segmentCount += len(segments(flight))
+	}
+
+	if maxStops := routeBuilder.constraints.MaxStops; maxStops != nil && segmentCount-1 > *maxStops {
+		return false
+	}
+
+	duration := path[len(path)-1].ArrivalTime.Sub(path[0].DepartureTime)
+
+	return routeBuilder.constraints.MaxDuration <= 0 || duration <= routeBuilder.constraints.MaxDuration
+}
+
+// Join rebuilds an interline itinerary from tickets that chain in order.
+func Join(tickets []domain.Flight) (domain.Flight, bool) {
+	if len(tickets) < 2 || len(tickets) > maxTickets {
+		return domain.Flight{}, false
+	}
+
+	for index := 1; index < len(tickets); index++ {
+		previousTicket, ticket := tickets[index-1], tickets[index]
+
+		if ticket.From != previousTicket.To || ticket.DepartureTime.Before(previousTicket.ArrivalTime) || ticket.Currency != previousTicket.Currency {
+			return domain.Flight{}, false
+		}
+	}
+
+	return combine(tickets), true
+}
+
+func combine(path []domain.Flight) domain.Flight {
+	firstFlight := path[0]
+	lastFlight := path[len(path)-1]
+
+	itinerary := domain.Flight{
+		FlightNumber:     firstFlight.FlightNumber,
+		From:             firstFlight.From,
+		To:               lastFlight.To,
+		DepartureTime:    firstFlight.DepartureTime,
+		ArrivalTime:      lastFlight.ArrivalTime,
+		Currency:         firstFlight.Currency,
+		VirtualInterline: true,
+	}
+
+	for _, flight := range path {
+		itinerary.Price += flight.Price
+		itinerary.Segments = append(itinerary.Segments, segments(flight)...)
+		itinerary.Tickets = append(itinerary.Tickets, flight.Reference)
+	}
+
+	itinerary.Reference = strings.Join(itinerary.Tickets, "+")
+
+	return itinerary
+}
+
+// segments falls back to a single segment for flights that do not detail theirs.
+func segments(flight domain.Flight) []domain.Segment {
+	if len(flight.Segments) > 0 {
+		return flight.Segments
+	}
+
+	return []domain.Segment{{
+		FlightNumber:  flight.FlightNumber,
+		From:          flight.From,
+		To:            flight.To,
+		DepartureTime: flight.DepartureTime,
+		ArrivalTime:   flight.ArrivalTime,
+	}}
+}
@@ -1,7 +1,8 @@
 package sorter
 
 import (
-	"sort"
+	"cmp"
+	"slices"
 	"strings"
 
 	"github.com/Orden14/flight-aggregator/src/domain"
@@ -22,45 +23,73 @@ const (
 	OrderDesc Order = "desc"
 )
 
-func NormalizeSortBy(inputValue string) SortBy {
+func ParseSortBy(inputValue string) (SortBy, bool) {
 	switch strings.ToLower(inputValue) {
 	case "price":
-		return SortByPrice
+		return SortByPrice, true
 	case "travel_time", "duration":
-		return SortByTravelTime
+		return SortByTravelTime, true
 	case "departure_date", "departure":
-		return SortByDepartureDate
+		return SortByDepartureDate, true
 	default:
-		return SortByPrice
+		return SortByPrice, false
 	}
 }
 
-func NormalizeOrder(inputValue string) Order {
+func NormalizeSortBy(inputValue string) SortBy {
+	sortBy, _ := ParseSortBy(inputValue)
+
+	return sortBy
+}
+
+func ParseOrder(inputValue string) (Order, bool) {
 	switch strings.ToLower(inputValue) {
+	case "asc", "ascending":
+		return OrderAsc, true
 	case "desc", "descending":
-		return OrderDesc
+		return OrderDesc, true
 	default:
-		return OrderAsc
+		return OrderAsc, false
 	}
 }
 
-func SortFlights(flights []domain.Flight, sortBy SortBy, sortOrder Order) {
-	compareFlights := func(i, j int) bool { return false }
+func NormalizeOrder(inputValue string) Order {
+	order, _ := ParseOrder(inputValue)
+
+	return order
+}
 
+// SortKey is the value flights are ordered by. Departure times are compared at
+// millisecond precision so that the key round-trips through a float64.
+func SortKey(flight domain.Flight, sortBy SortBy) float64 {
 	switch sortBy {
 	case SortByTravelTime:
-		compareFlights = func(i, j int) bool { return flights[i].Duration() < flights[j].Duration() }
+		return flight.Duration().Seconds()
 	case SortByDepartureDate:
-		compareFlights = func(i, j int) bool { return flights[i].DepartureTime.Before(flights[j].DepartureTime) }
+		return float64(flight.DepartureTime.UnixMilli())
 	default: // SortByPrice
-		compareFlights = func(i, j int) bool { return flights[i].Price < flights[j].Price }
+		return flight.Price
 	}
+}
 
-	sort.SliceStable(flights, func(i, j int) bool {
-		if sortOrder == OrderAsc {
-			return compareFlights(i, j)
-		}
+// CompareKeys orders (key, reference) pairs, the reference breaking ties so that the
+// order is total and pages cut from it are stable.
+func CompareKeys(keyA float64, referenceA string, keyB float64, referenceB string, sortOrder Order) int {
+	comparison := cmp.Compare(keyA, keyB)
 
-		return compareFlights(j, i)
+	if comparison == 0 {
+		comparison = strings.Compare(referenceA, referenceB)
+	}
+
+	if sortOrder == OrderAsc {
+		return comparison
+	}
+
+	return -comparison
+}
+
+func SortFlights(flights []domain.Flight, sortBy SortBy, sortOrder Order) {
+	slices.SortStableFunc(flights, func(flightA domain.Flight, flightB domain.Flight) int {
+		return CompareKeys(SortKey(flightA, sortBy), flightA.Reference, SortKey(flightB, sortBy), flightB.Reference, sortOrder)
 	})
 }
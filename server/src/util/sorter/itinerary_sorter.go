@@ -0,0 +1,25 @@
+package sorter
+
+import (
+	"slices"
+
+	"github.com/Orden14/flight-aggregator/src/domain"
+)
+
+// ItineraryKey mirrors SortKey: the total price, the time spent flying, or the first departure.
+func ItineraryKey(itinerary domain.Itinerary, sortBy SortBy) float64 {
+	switch sortBy {
+	case SortByTravelTime:
+		return float64(itinerary.TravelTimeMinutes * 60)
+	case SortByDepartureDate:
+		return float64(itinerary.DepartureTime.UnixMilli())
+	default: // SortByPrice
+		return itinerary.Price
+	}
+}
+
+func SortItineraries(itineraries []domain.Itinerary, sortBy SortBy, sortOrder Order) {
+	slices.SortStableFunc(itineraries, func(itineraryA domain.Itinerary, itineraryB domain.Itinerary) int {
+		return CompareKeys(ItineraryKey(itineraryA, sortBy), itineraryA.Reference, ItineraryKey(itineraryB, sortBy), itineraryB.Reference, sortOrder)
+	})
+}
@@ -0,0 +1,90 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/handler"
+	"github.com/Orden14/flight-aggregator/src/service"
+	"github.com/Orden14/flight-aggregator/src/util/airport"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAirportDatasetDescribesAirports(t *testing.T) {
+	haneda, isKnown := airport.Lookup("HND")
+	require.True(t, isKnown)
+	require.Equal(t, "TYO", haneda.City)
+	require.Equal(t, "JP", haneda.Country)
+	require.Equal(t, "Asia/Tokyo", haneda.Timezone)
+	require.Equal(t, "Asia/Tokyo", airport.Location("NRT").String())
+
+	charlesDeGaulle, _ := airport.Lookup("CDG")
+	heathrow, _ := airport.Lookup("LHR")
+	require.InDelta(t, 347, airport.DistanceKm(charlesDeGaulle, heathrow), 5)
+}
+
+func TestAirportExpandsCityCodes(t *testing.T) {
+	require.Equal(t, []string{"HND", "NRT"}, airport.Expand("TYO"))
+	require.Equal(t, []string{"BVA", "CDG", "ORY"}, airport.Expand("PAR"))
+	require.Equal(t, []string{"CDG"}, airport.Expand("CDG"))
+	require.Equal(t, []string{"DWC", "DXB"}, airport.Expand("DXB"))
+	require.Equal(t, []string{"OAK", "SFO"}, airport.Expand("SFO"))
+	require.Equal(t, []string{"XXX"}, airport.Expand("XXX"))
+	require.True(t, airport.Known("PAR"))
+	require.False(t, airport.Known("XXX"))
+}
+
+func TestAirportLocationFallsBackToUTC(t *testing.T) {
+	require.Equal(t, "Asia/Tokyo", airport.Location("HND").String())
+	require.Equal(t, time.UTC, airport.Location("XXX"))
+}
+
+func TestAirportNearWithinRadius(t *testing.T) {
+	require.Equal(t, []string{"BVA", "CDG", "ORY"}, airport.Near("CDG", 100))
+	require.Equal(t, []string{"CDG"}, airport.Near("CDG", 10))
+	require.Contains(t, airport.Near("CDG", 400), "LHR")
+}
+
+func cityFlightService(t *testing.T) service.FlightService {
+	return fixedService(fixedRepo("cities",
+		routeFlight(t, "CDG-HND", "CDG", "HND", "2026-01-01T10:00:00Z", "2026-01-01T23:00:00Z", 900),
+		routeFlight(t, "ORY-NRT", "ORY", "NRT", "2026-01-01T12:00:00Z", "2026-01-02T01:00:00Z", 800),
+		routeFlight(t, "LHR-HND", "LHR", "HND", "2026-01-01T09:00:00Z", "2026-01-01T23:00:00Z", 700),
+		routeFlight(t, "NRT-ORY", "NRT", "ORY", "2026-01-08T10:00:00Z", "2026-01-08T22:00:00Z", 500),
+	))
+}
+
+func servedReferences(t *testing.T, flightService service.FlightService, target string) []string {
+	recorder, body := getJSON[pageBody](t, handler.NewFlightHandler(flightService), target)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+	return flightReferences(body.Items)
+}
+
+func TestSearchExpandsCityCodesAndRadius(t *testing.T) {
+	flightService := cityFlightService(t)
+
+	require.Equal(t, []string{"ORY-NRT", "CDG-HND"}, servedReferences(t, flightService, "/flights?from=PAR&to=TYO"))
+	require.Equal(t, []string{"LHR-HND", "CDG-HND"}, servedReferences(t, flightService, "/flights?to=HND"))
+	require.Equal(t, []string{"ORY-NRT", "CDG-HND"}, servedReferences(t, flightService, "/flights?near=CDG&radius_km=100&to=TYO"))
+	require.Equal(t, []string{"LHR-HND", "ORY-NRT", "CDG-HND"}, servedReferences(t, flightService, "/flights?from=CDG&radius_km=400&to=HND&to_radius_km=80"))
+}
+
+func TestRoundTripPairsAirportsOfTheSameCity(t *testing.T) {
+	_, body := getJSON[roundTripBody](t, testRouter(cityFlightService(t)), "/flights/round-trip?from=PAR&to=TYO")
+	require.Equal(t, []string{"ORY-NRT+NRT-ORY", "CDG-HND+NRT-ORY"}, itineraryReferences(body.Items))
+	require.Equal(t, (6*24+11)*60, body.Items[1].StayMinutes)
+}
+
+func TestNearRequiresKnownAirportAndRadius(t *testing.T) {
+	recorder := serveFlights(t, cityFlightService(t), "/flights?near=XXX&radius_km=100")
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `"name":"near"`)
+
+	recorder = serveFlights(t, cityFlightService(t), "/flights?from=CDG&near=ORY&radius_km=5000&to_radius_km=50")
+
+	for _, name := range []string{"near", "radius_km", "to_radius_km"} {
+		require.Contains(t, recorder.Body.String(), `"name":"`+name+`"`)
+	}
+}
@@ -0,0 +1,165 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/config"
+	"github.com/Orden14/flight-aggregator/src/domain"
+	"github.com/Orden14/flight-aggregator/src/repository"
+	"github.com/Orden14/flight-aggregator/src/service"
+	"github.com/stretchr/testify/require"
+)
+
+func switchableRepo(failing *atomic.Bool, calls *atomic.Int32) *MockRepo {
+	return &MockRepo{
+		ProviderName: "switchable",
+		FetchFunc: func(ctx context.Context) ([]domain.Flight, error) {
+			calls.Add(1)
+
+			if failing.Load() {
+				return nil, errors.New("upstream unavailable")
+			}
+
+			return []domain.Flight{}, nil
+		},
+	}
+}
+
+func TestCircuitBreakerOpensAfterThreshold(t *testing.T) {
+	var failing atomic.Bool
+	var calls atomic.Int32
+
+	failing.Store(true)
+
+	circuitBreaker := repository.NewCircuitBreakerRepository(switchableRepo(&failing, &calls), config.CircuitBreakerConfig{
+		FailureThreshold: 2,
+		CoolDown:         time.Minute,
+	})
+
+	for range 2 {
+		_, err := circuitBreaker.Fetch(context.Background())
+		require.Error(t, err)
+		require.False(t, errors.Is(err, repository.ErrCircuitOpen))
+	}
+
+	require.Equal(t, repository.CircuitOpen, circuitBreaker.State())
+
+	_, err := circuitBreaker.Fetch(context.Background())
+	require.ErrorIs(t, err, repository.ErrCircuitOpen)
+	require.Equal(t, int32(2), calls.Load())
+}
+
+func TestCircuitBreakerHalfOpenProbe(t *testing.T) {
+	var failing atomic.Bool
+	var calls atomic.Int32
+
+	failing.Store(true)
+
+	circuitBreaker := repository.NewCircuitBreakerRepository(switchableRepo(&failing, &calls), config.CircuitBreakerConfig{
+		FailureThreshold: 1,
+		CoolDown:         20 * time.Millisecond,
+	})
+
+	_, err := circuitBreaker.Fetch(context.Background())
+	require.Error(t, err)
+	require.Equal(t, repository.CircuitOpen, circuitBreaker.State())
+
+	time.Sleep(30 * time.Millisecond)
+	require.Equal(t, repository.CircuitHalfOpen, circuitBreaker.State())
+
+	_, err = circuitBreaker.Fetch(context.Background())
+	require.Error(t, err)
+	require.Equal(t, repository.CircuitOpen, circuitBreaker.State())
+
+	time.Sleep(30 * time.Millisecond)
+	failing.Store(false)
+
+	_, err = circuitBreaker.Fetch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, repository.CircuitClosed, circuitBreaker.State())
+	require.Equal(t, int32(3), calls.Load())
+}
+
+func TestCircuitBreakerIgnoresCallsAdmittedBeforeTheProbe(t *testing.T) {
+	slowFailure := make(chan struct{})
+	probe := make(chan struct{})
+	outcomes := []func() error{
+		func() error { <-slowFailure; return errors.New("slow failure") },
+		func() error { return errors.New("fast failure") },
+		func() error { <-probe; return nil },
+	}
+
+	var calls atomic.Int32
+
+	circuitBreaker := repository.NewCircuitBreakerRepository(&MockRepo{
+		ProviderName: "probed",
+		FetchFunc: func(ctx context.Context) ([]domain.Flight, error) {
+			return []domain.Flight{}, outcomes[calls.Add(1)-1]()
+		},
+	}, config.CircuitBreakerConfig{FailureThreshold: 1, CoolDown: 10 * time.Millisecond})
+
+	slowErr := make(chan error, 1)
+	probeErr := make(chan error, 1)
+
+	go func() {
+		_, err := circuitBreaker.Fetch(context.Background())
+		slowErr <- err
+	}()
+
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	_, err := circuitBreaker.Fetch(context.Background())
+	require.Error(t, err)
+	require.Equal(t, repository.CircuitOpen, circuitBreaker.State())
+
+	time.Sleep(20 * time.Millisecond)
+
+	go func() {
+		_, err := circuitBreaker.Fetch(context.Background())
+		probeErr <- err
+	}()
+
+	require.Eventually(t, func() bool { return calls.Load() == 3 }, time.Second, time.Millisecond)
+
+	close(slowFailure)
+	require.Error(t, <-slowErr)
+	require.Equal(t, repository.CircuitHalfOpen, circuitBreaker.State())
+
+	_, err = circuitBreaker.Fetch(context.Background())
+	require.ErrorIs(t, err, repository.ErrCircuitOpen)
+
+	close(probe)
+	require.NoError(t, <-probeErr)
+	require.Equal(t, repository.CircuitClosed, circuitBreaker.State())
+	require.Equal(t, int32(3), calls.Load())
+}
+
+func TestCircuitStateInProviderStatus(t *testing.T) {
+	var failing atomic.Bool
+	var calls atomic.Int32
+
+	failing.Store(true)
+
+	circuitBreaker := repository.NewCircuitBreakerRepository(switchableRepo(&failing, &calls), config.CircuitBreakerConfig{
+		FailureThreshold: 1,
+		CoolDown:         time.Minute,
+	})
+
+	okRepo := &MockRepo{ProviderName: "healthy"}
+
+	flightService := service.NewFlightService(1, circuitBreaker, okRepo)
+
+	result, err := flightService.GetFlights(context.Background(), service.FlightQuery{})
+	require.NoError(t, err)
+	require.Equal(t, string(repository.CircuitOpen), result.Providers[0].Circuit)
+	require.Empty(t, result.Providers[1].Circuit)
+
+	result, err = flightService.GetFlights(context.Background(), service.FlightQuery{})
+	require.NoError(t, err)
+	require.Contains(t, result.Providers[0].Error, repository.ErrCircuitOpen.Error())
+	require.Equal(t, int32(1), calls.Load())
+}
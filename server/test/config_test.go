@@ -0,0 +1,137 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/config"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadProvidersFromConfigFile(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+providers:
+  - name: j-server1
+    type: server1
+    base_url: http://j-server1:4001/
+    timeout: 2s
+  - name: partner
+    enabled: false
+    base_url: https://partner.example.com
+    path: /offers
+    auth:
+      type: bearer
+      token: ${PARTNER_TOKEN}
+`), 0o600))
+
+	t.Setenv("CONFIG_FILE", configPath)
+	t.Setenv("PARTNER_TOKEN", "secret")
+
+	appConfig, err := config.Load()
+	require.NoError(t, err)
+	require.Len(t, appConfig.Providers, 2)
+
+	require.Equal(t, "j-server1", appConfig.Providers[0].Name)
+	require.Equal(t, config.ProviderTypeServer1, appConfig.Providers[0].Type)
+	require.Equal(t, "http://j-server1:4001", appConfig.Providers[0].BaseURL)
+	require.Equal(t, 2*time.Second, appConfig.Providers[0].Timeout)
+	require.True(t, appConfig.Providers[0].IsEnabled())
+	require.Equal(t, config.ParsingLenient, appConfig.Providers[0].Parsing)
+
+	require.Equal(t, config.ProviderTypeJSON, appConfig.Providers[1].Type)
+	require.False(t, appConfig.Providers[1].IsEnabled())
+	require.Equal(t, "secret", appConfig.Providers[1].Auth.Token)
+}
+
+func TestLoadProvidersFallsBackToEnv(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("JSERVER1_NAME", "j-server1")
+	t.Setenv("JSERVER1_PORT", "4001")
+	t.Setenv("JSERVER2_NAME", "j-server2")
+	t.Setenv("JSERVER2_PORT", "4002")
+
+	appConfig, err := config.Load()
+	require.NoError(t, err)
+	require.Len(t, appConfig.Providers, 2)
+	require.Equal(t, config.ProviderTypeServer1, appConfig.Providers[0].Type)
+	require.Equal(t, "http://j-server1:4001", appConfig.Providers[0].BaseURL)
+	require.Equal(t, config.ProviderTypeServer2, appConfig.Providers[1].Type)
+	require.Equal(t, "http://j-server2:4002", appConfig.Providers[1].BaseURL)
+}
+
+func TestLoadRejectsUnknownProviderType(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+providers:
+  - name: broken
+    type: soap
+    base_url: http://broken
+`), 0o600))
+
+	t.Setenv("CONFIG_FILE", configPath)
+
+	_, err := config.Load()
+	require.Error(t, err)
+}
+
+func TestLoadRejectsHeaderAuthWithoutHeaderName(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+providers:
+  - name: partner
+    base_url: http://partner
+    auth:
+      type: header
+      token: secret
+`), 0o600))
+
+	t.Setenv("CONFIG_FILE", configPath)
+
+	_, err := config.Load()
+	require.ErrorContains(t, err, "provider partner: auth type header requires a header name")
+}
+
+func TestLoadParsesRetryStatusCodesFromEnv(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("JSERVER1_NAME", "j-server1")
+	t.Setenv("JSERVER1_PORT", "4001")
+	t.Setenv("JSERVER2_NAME", "j-server2")
+	t.Setenv("JSERVER2_PORT", "4002")
+
+	appConfig, err := config.Load()
+	require.NoError(t, err)
+	require.Equal(t, []int{429, 500, 502, 503, 504}, appConfig.Retry.RetryableStatusCodes)
+
+	t.Setenv("RETRY_STATUS_CODES", "500, 503")
+
+	appConfig, err = config.Load()
+	require.NoError(t, err)
+	require.Equal(t, []int{500, 503}, appConfig.Retry.RetryableStatusCodes)
+
+	t.Setenv("RETRY_STATUS_CODES", "500,abc")
+
+	_, err = config.Load()
+	require.ErrorContains(t, err, "abc")
+}
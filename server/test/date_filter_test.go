@@ -0,0 +1,76 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/service"
+	"github.com/Orden14/flight-aggregator/src/util/filter"
+	"github.com/stretchr/testify/require"
+)
+
+func dateFilterService(t *testing.T) service.FlightService {
+	return fixedService(fixedRepo("dated",
+		routeFlight(t, "LATE-UTC", "CDG", "HND", "2026-01-01T23:30:00Z", "2026-01-02T13:30:00Z", 0),
+		routeFlight(t, "MORNING", "CDG", "HND", "2026-01-01T08:00:00Z", "2026-01-01T22:00:00Z", 0),
+		routeFlight(t, "NEXT-WEEK", "CDG", "HND", "2026-01-08T12:00:00Z", "2026-01-09T02:00:00Z", 0),
+	))
+}
+
+func TestFilterDepartureDateUsesAirportLocalTime(t *testing.T) {
+	flightService := dateFilterService(t)
+
+	references := searchReferences(t, flightService, service.FlightQuery{DepartureDateFrom: "2026-01-01", DepartureDateTo: "2026-01-01"})
+	require.ElementsMatch(t, []string{"MORNING"}, references)
+
+	references = searchReferences(t, flightService, service.FlightQuery{DepartureDateFrom: "2026-01-02", DepartureDateTo: "2026-01-02"})
+	require.ElementsMatch(t, []string{"LATE-UTC"}, references)
+}
+
+func TestFilterDepartureDateRange(t *testing.T) {
+	references := searchReferences(t, dateFilterService(t), service.FlightQuery{DepartureDateFrom: "2026-01-02"})
+
+	require.ElementsMatch(t, []string{"LATE-UTC", "NEXT-WEEK"}, references)
+}
+
+func TestFilterTimeWindowsUseAirportLocalTime(t *testing.T) {
+	flightService := dateFilterService(t)
+
+	references := searchReferences(t, flightService, service.FlightQuery{
+		DepartureWindow: &filter.TimeWindow{From: 8 * time.Hour, To: 14 * time.Hour},
+	})
+	require.ElementsMatch(t, []string{"MORNING", "NEXT-WEEK"}, references)
+
+	references = searchReferences(t, flightService, service.FlightQuery{
+		ArrivalWindow: &filter.TimeWindow{From: 20 * time.Hour, To: 6 * time.Hour},
+	})
+	require.ElementsMatch(t, []string{"LATE-UTC"}, references)
+}
+
+func TestHandlerParsesDateFilters(t *testing.T) {
+	flightService := &StubFlightService{}
+
+	recorder := serveFlights(t, flightService, "/flights?departure_date=2026-01-01&departure_time_from=08:00&departure_time_to=14:00&arrival_time_from=18:00")
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	require.Equal(t, "2026-01-01", flightService.LastQuery.DepartureDateFrom)
+	require.Equal(t, "2026-01-01", flightService.LastQuery.DepartureDateTo)
+	require.Equal(t, &filter.TimeWindow{From: 8 * time.Hour, To: 14 * time.Hour}, flightService.LastQuery.DepartureWindow)
+	require.Equal(t, 18*time.Hour, flightService.LastQuery.ArrivalWindow.From)
+}
+
+func TestHandlerRejectsMalformedDates(t *testing.T) {
+	for _, target := range []string{
+		"/flights?departure_date=2026-13-01",
+		"/flights?departure_date=01/01/2026",
+		"/flights?departure_date_from=2026-01-05&departure_date_to=2026-01-01",
+		"/flights?departure_date=2026-01-01&departure_date_to=2026-01-02",
+		"/flights?departure_time_from=8h",
+		"/flights?arrival_time_to=25:00",
+	} {
+		recorder := serveFlights(t, &StubFlightService{}, target)
+		require.Equal(t, http.StatusBadRequest, recorder.Code, target)
+		require.Contains(t, recorder.Body.String(), "invalid", target)
+	}
+}
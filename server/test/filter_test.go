@@ -0,0 +1,124 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/domain"
+	"github.com/Orden14/flight-aggregator/src/util/filter"
+	"github.com/stretchr/testify/require"
+)
+
+func filterSample(t *testing.T) domain.Flight {
+	return domain.Flight{
+		Reference:     "F1",
+		FlightNumber:  "KE902",
+		From:          "CDG",
+		To:            "HND",
+		Price:         880,
+		DepartureTime: mustRFC3339(t, "2026-01-01T09:30:00Z"),
+		ArrivalTime:   mustRFC3339(t, "2026-01-02T00:30:00Z"),
+		Segments: []domain.Segment{
+			{FlightNumber: "KE902", From: "CDG", To: "ICN", DepartureTime: mustRFC3339(t, "2026-01-01T09:30:00Z"), ArrivalTime: mustRFC3339(t, "2026-01-01T18:00:00Z")},
+			{FlightNumber: "NH712", From: "ICN", To: "HND", DepartureTime: mustRFC3339(t, "2026-01-01T20:00:00Z"), ArrivalTime: mustRFC3339(t, "2026-01-02T00:30:00Z")},
+		},
+	}
+}
+
+func TestCarrierCode(t *testing.T) {
+	require.Equal(t, "AF", filter.CarrierCode("AF276"))
+	require.Equal(t, "AY", filter.CarrierCode("AY1582"))
+	require.Equal(t, "U2", filter.CarrierCode("U21234"))
+	require.Equal(t, "3U", filter.CarrierCode("3U8888"))
+	require.Equal(t, "AF", filter.CarrierCode("af 276"))
+	require.Equal(t, "AFR", filter.CarrierCode("AFR276"))
+}
+
+func TestAirportPredicates(t *testing.T) {
+	flight := filterSample(t)
+
+	require.True(t, filter.DepartureAirport("CDG")(flight))
+	require.False(t, filter.DepartureAirport("ORY")(flight))
+	require.True(t, filter.ArrivalAirport("HND")(flight))
+	require.False(t, filter.ArrivalAirport("ICN")(flight))
+}
+
+func TestStopPredicates(t *testing.T) {
+	flight := filterSample(t)
+
+	require.False(t, filter.DirectOnly()(flight))
+	require.True(t, filter.MaxStops(1)(flight))
+	require.False(t, filter.MaxStops(0)(flight))
+	require.True(t, filter.LayoverBetween(time.Hour, 3*time.Hour)(flight))
+	require.False(t, filter.LayoverBetween(3*time.Hour, 0)(flight))
+	require.False(t, filter.LayoverBetween(0, time.Hour)(flight))
+}
+
+func TestPricePredicates(t *testing.T) {
+	flight := filterSample(t)
+
+	require.True(t, filter.MinPrice(880)(flight))
+	require.False(t, filter.MinPrice(881)(flight))
+	require.True(t, filter.MaxPrice(880)(flight))
+	require.False(t, filter.MaxPrice(879.99)(flight))
+}
+
+func TestAirlinePredicates(t *testing.T) {
+	flight := filterSample(t)
+
+	require.True(t, filter.Airline("AF", "KE")(flight))
+	require.False(t, filter.Airline("NH")(flight))
+	require.False(t, filter.ExcludeAirline("NH")(flight))
+	require.True(t, filter.ExcludeAirline("AF", "JL")(flight))
+}
+
+func TestFlightNumberPredicate(t *testing.T) {
+	flight := filterSample(t)
+
+	require.True(t, filter.FlightNumber("KE902")(flight))
+	require.True(t, filter.FlightNumber("nh 712")(flight))
+	require.False(t, filter.FlightNumber("KE711")(flight))
+}
+
+func TestApplyCombinesPredicates(t *testing.T) {
+	cheap := filterSample(t)
+	cheap.Reference = "CHEAP"
+	cheap.Price = 500
+
+	expensive := filterSample(t)
+	expensive.Reference = "EXPENSIVE"
+	expensive.Price = 1500
+
+	flights := filter.Apply([]domain.Flight{cheap, expensive}, filter.Airline("KE"), filter.MaxPrice(1000))
+
+	require.Len(t, flights, 1)
+	require.Equal(t, "CHEAP", flights[0].Reference)
+	require.Len(t, filter.Apply([]domain.Flight{cheap, expensive}), 2)
+}
+
+func TestHandlerParsesPriceAndAirlineFilters(t *testing.T) {
+	flightService := &StubFlightService{}
+
+	recorder := serveFlights(t, flightService, "/flights?min_price=100&max_price=900.5&airline=af,jl&airline=NH&exclude_airline=KE&flight_number=AF276")
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	require.Equal(t, 100.0, *flightService.LastQuery.MinPrice)
+	require.Equal(t, 900.5, *flightService.LastQuery.MaxPrice)
+	require.Equal(t, []string{"AF", "JL", "NH"}, flightService.LastQuery.Airlines)
+	require.Equal(t, []string{"KE"}, flightService.LastQuery.ExcludedAirlines)
+	require.Equal(t, "AF276", flightService.LastQuery.FlightNumber)
+}
+
+func TestHandlerRejectsMalformedPrices(t *testing.T) {
+	for _, target := range []string{
+		"/flights?min_price=cheap",
+		"/flights?max_price=-5",
+		"/flights?min_price=NaN",
+		"/flights?max_price=Inf",
+		"/flights?min_price=900&max_price=100",
+	} {
+		recorder := serveFlights(t, &StubFlightService{}, target)
+		require.Equal(t, http.StatusBadRequest, recorder.Code, target)
+	}
+}
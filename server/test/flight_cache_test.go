@@ -0,0 +1,220 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/domain"
+	"github.com/Orden14/flight-aggregator/src/service"
+	"github.com/stretchr/testify/require"
+)
+
+func countingRepo(t *testing.T, calls *atomic.Int32, delay time.Duration) *MockRepo {
+	departureTime := tTime(t, "2026-01-01T10:00:00Z")
+	arrivalTime := tTime(t, "2026-01-01T20:00:00Z")
+
+	return &MockRepo{
+		ProviderName: "counting",
+		FetchFunc: func(ctx context.Context) ([]domain.Flight, error) {
+			calls.Add(1)
+			time.Sleep(delay)
+
+			return []domain.Flight{
+				{
+					Reference:     "CACHED",
+					From:          "CDG",
+					To:            "HND",
+					Price:         700,
+					DepartureTime: departureTime,
+					ArrivalTime:   arrivalTime,
+				},
+			}, nil
+		},
+	}
+}
+
+func TestCacheServesHitsWithinTTL(t *testing.T) {
+	var calls atomic.Int32
+
+	flightService := service.NewFlightServiceWithOptions(service.FlightServiceOptions{
+		RepositoryTimeout: time.Second,
+		CacheTTL:          50 * time.Millisecond,
+	}, countingRepo(t, &calls, 0))
+
+	result, err := flightService.GetFlights(context.Background(), service.FlightQuery{})
+	require.NoError(t, err)
+	require.Equal(t, string(service.CacheStatusMiss), result.Providers[0].Cache)
+
+	result, err = flightService.GetFlights(context.Background(), service.FlightQuery{})
+	require.NoError(t, err)
+	require.Equal(t, string(service.CacheStatusHit), result.Providers[0].Cache)
+	require.Len(t, result.Flights, 1)
+	require.Equal(t, int32(1), calls.Load())
+
+	time.Sleep(60 * time.Millisecond)
+
+	result, err = flightService.GetFlights(context.Background(), service.FlightQuery{})
+	require.NoError(t, err)
+	require.Equal(t, string(service.CacheStatusMiss), result.Providers[0].Cache)
+	require.Equal(t, int32(2), calls.Load())
+}
+
+func TestCacheCollapsesConcurrentMisses(t *testing.T) {
+	var calls atomic.Int32
+	var waitGroup sync.WaitGroup
+
+	flightService := service.NewFlightServiceWithOptions(service.FlightServiceOptions{
+		RepositoryTimeout: time.Second,
+		CacheTTL:          time.Minute,
+	}, countingRepo(t, &calls, 50*time.Millisecond))
+
+	flightCounts := make(chan int, 10)
+	errs := make(chan error, 10)
+
+	for range 10 {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			result, err := flightService.GetFlights(context.Background(), service.FlightQuery{})
+
+			if err != nil {
+				errs <- err
+
+				return
+			}
+
+			flightCounts <- len(result.Flights)
+		}()
+	}
+
+	waitGroup.Wait()
+	close(flightCounts)
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	for flightCount := range flightCounts {
+		require.Equal(t, 1, flightCount)
+	}
+
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestCacheServesStaleWhileRevalidating(t *testing.T) {
+	var completedFetches atomic.Int32
+
+	revalidation := make(chan struct{})
+	departureTime := tTime(t, "2026-01-01T10:00:00Z")
+
+	flightService := service.NewFlightServiceWithOptions(service.FlightServiceOptions{
+		RepositoryTimeout:         time.Second,
+		CacheTTL:                  30 * time.Millisecond,
+		CacheStaleWhileRevalidate: time.Minute,
+	}, &MockRepo{
+		ProviderName: "revalidated",
+		FetchFunc: func(ctx context.Context) ([]domain.Flight, error) {
+			if completedFetches.Load() > 0 {
+				<-revalidation
+			}
+
+			defer completedFetches.Add(1)
+
+			return []domain.Flight{{Reference: "CACHED", DepartureTime: departureTime, ArrivalTime: departureTime.Add(10 * time.Hour)}}, nil
+		},
+	})
+
+	_, err := flightService.GetFlights(context.Background(), service.FlightQuery{})
+	require.NoError(t, err)
+
+	time.Sleep(40 * time.Millisecond)
+
+	result, err := flightService.GetFlights(context.Background(), service.FlightQuery{})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), completedFetches.Load())
+	require.True(t, result.Providers[0].Stale)
+	require.Equal(t, string(service.CacheStatusStale), result.Providers[0].Cache)
+	require.Len(t, result.Flights, 1)
+
+	close(revalidation)
+
+	require.Eventually(t, func() bool { return completedFetches.Load() == 2 }, time.Second, 5*time.Millisecond)
+
+	require.Eventually(t, func() bool {
+		result, err := flightService.GetFlights(context.Background(), service.FlightQuery{})
+
+		return err == nil && result.Providers[0].Cache == string(service.CacheStatusHit)
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestCacheServesStaleOnError(t *testing.T) {
+	var failing atomic.Bool
+
+	flightRepository := &MockRepo{
+		ProviderName: "flaky",
+		FetchFunc: func(ctx context.Context) ([]domain.Flight, error) {
+			if failing.Load() {
+				return nil, errors.New("upstream unavailable")
+			}
+
+			return []domain.Flight{{Reference: "SNAPSHOT"}}, nil
+		},
+	}
+
+	flightService := service.NewFlightServiceWithOptions(service.FlightServiceOptions{
+		RepositoryTimeout: time.Second,
+		CacheTTL:          10 * time.Millisecond,
+		CacheStaleIfError: time.Minute,
+	}, flightRepository)
+
+	_, err := flightService.GetFlights(context.Background(), service.FlightQuery{})
+	require.NoError(t, err)
+
+	failing.Store(true)
+	time.Sleep(20 * time.Millisecond)
+
+	result, err := flightService.GetFlights(context.Background(), service.FlightQuery{})
+	require.NoError(t, err)
+	require.Len(t, result.Flights, 1)
+	require.Equal(t, "SNAPSHOT", result.Flights[0].Reference)
+	require.Equal(t, service.ProviderStatusStale, result.Providers[0].Status)
+	require.True(t, result.Providers[0].Stale)
+	require.Equal(t, "provider error", result.Providers[0].Error)
+}
+
+func TestCacheStaleIfErrorWindowIsBounded(t *testing.T) {
+	var failing atomic.Bool
+
+	flightRepository := &MockRepo{
+		ProviderName: "flaky",
+		FetchFunc: func(ctx context.Context) ([]domain.Flight, error) {
+			if failing.Load() {
+				return nil, errors.New("upstream unavailable")
+			}
+
+			return []domain.Flight{{Reference: "SNAPSHOT"}}, nil
+		},
+	}
+
+	flightService := service.NewFlightServiceWithOptions(service.FlightServiceOptions{
+		RepositoryTimeout: time.Second,
+		CacheTTL:          10 * time.Millisecond,
+		CacheStaleIfError: 10 * time.Millisecond,
+	}, flightRepository)
+
+	_, err := flightService.GetFlights(context.Background(), service.FlightQuery{})
+	require.NoError(t, err)
+
+	failing.Store(true)
+	time.Sleep(30 * time.Millisecond)
+
+	_, err = flightService.GetFlights(context.Background(), service.FlightQuery{})
+	require.Error(t, err)
+}
@@ -0,0 +1,72 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/Orden14/flight-aggregator/src/config"
+	"github.com/Orden14/flight-aggregator/src/domain"
+	"github.com/Orden14/flight-aggregator/src/problem"
+	"github.com/Orden14/flight-aggregator/src/repository"
+	"github.com/Orden14/flight-aggregator/src/service"
+	"github.com/stretchr/testify/require"
+)
+
+type detailBody struct {
+	Flight      domain.Flight `json:"flight"`
+	OfferedBy   []string      `json:"offered_by"`
+	OffersCount int           `json:"offers_count"`
+	Offers      []struct {
+		Provider string          `json:"provider"`
+		Price    float64         `json:"price"`
+		Raw      json.RawMessage `json:"raw"`
+	} `json:"offers"`
+}
+
+func TestFlightDetailListsEveryOffer(t *testing.T) {
+	flightService := service.NewFlightService(2,
+		fixedRepo("expensive", streamFlight(t, "D1", 900), streamFlight(t, "D2", 100)),
+		fixedRepo("cheap", streamFlight(t, "D1", 600)),
+	)
+
+	recorder, body := getJSON[detailBody](t, testRouter(flightService), "/flights/D1")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "D1", body.Flight.Reference)
+	require.Equal(t, 600.0, body.Flight.Price)
+	require.Equal(t, 2, body.OffersCount)
+	require.Equal(t, []string{"cheap", "expensive"}, body.OfferedBy)
+	require.Equal(t, 900.0, body.Offers[1].Price)
+	require.Nil(t, body.Offers[0].Raw)
+}
+
+func TestFlightDetailUnknownReference(t *testing.T) {
+	flightService := service.NewFlightService(2,
+		fixedRepo("only", streamFlight(t, "D1", 600)),
+	)
+
+	recorder, _ := getJSON[detailBody](t, testRouter(flightService), "/flights/NOPE")
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+	require.Equal(t, problem.ContentType, recorder.Header().Get("Content-Type"))
+	require.Contains(t, recorder.Body.String(), problem.TypeFlightNotFound)
+}
+
+func TestFlightDetailRejectsInvalidRawParameter(t *testing.T) {
+	recorder, _ := getJSON[detailBody](t, testRouter(service.NewFlightService(2)), "/flights/D1?raw=maybe")
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `"name":"raw"`)
+}
+
+func TestFlightDetailIncludesRawProviderRecord(t *testing.T) {
+	flightRepository := repository.NewServer1FlightRepository(payloadConfig(t, server1PayloadWithBadRecord, config.ParsingLenient), testRetryPolicy(1))
+	router := testRouter(service.NewFlightService(2, flightRepository))
+
+	recorder, body := getJSON[detailBody](t, router, "/flights/A1?raw=true")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Len(t, body.Offers, 1)
+
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(body.Offers[0].Raw, &raw))
+	require.Equal(t, "A1", raw["bookingId"])
+	require.Equal(t, "JL046", raw["flightNumber"])
+}
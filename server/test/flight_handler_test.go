@@ -0,0 +1,215 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/config"
+	"github.com/Orden14/flight-aggregator/src/domain"
+	"github.com/Orden14/flight-aggregator/src/handler"
+	"github.com/Orden14/flight-aggregator/src/httpserver"
+	"github.com/Orden14/flight-aggregator/src/problem"
+	"github.com/Orden14/flight-aggregator/src/repository"
+	"github.com/Orden14/flight-aggregator/src/service"
+	"github.com/Orden14/flight-aggregator/src/util/sorter"
+	"github.com/stretchr/testify/require"
+)
+
+var _ service.FlightService = (*StubFlightService)(nil)
+
+type StubFlightService struct {
+	LastQuery service.FlightQuery
+	Result    *service.FlightSearchResult
+	Err       error
+}
+
+func (s *StubFlightService) GetFlights(ctx context.Context, query service.FlightQuery) (*service.FlightSearchResult, error) {
+	s.LastQuery = query
+
+	if s.Result == nil && s.Err == nil {
+		return &service.FlightSearchResult{}, nil
+	}
+
+	return s.Result, s.Err
+}
+
+func (s *StubFlightService) StreamFlights(ctx context.Context, query service.FlightQuery, onBatch func(service.ProviderBatch)) (*service.FlightSearchResult, error) {
+	return s.GetFlights(ctx, query)
+}
+
+func (s *StubFlightService) GetFlight(ctx context.Context, reference string, mode service.FetchMode) (*service.FlightDetail, error) {
+	return nil, service.ErrFlightNotFound
+}
+
+func (s *StubFlightService) SearchRoundTrips(ctx context.Context, query service.RoundTripQuery) (*service.ItinerarySearchResult, error) {
+	return &service.ItinerarySearchResult{}, nil
+}
+
+func (s *StubFlightService) SearchMultiCity(ctx context.Context, query service.MultiCityQuery) (*service.ItinerarySearchResult, error) {
+	return &service.ItinerarySearchResult{}, nil
+}
+
+func (s *StubFlightService) PriceCalendar(ctx context.Context, query service.CalendarQuery) (*service.PriceCalendar, error) {
+	return &service.PriceCalendar{}, nil
+}
+
+func (s *StubFlightService) Reload(options service.FlightServiceOptions, repositories ...repository.FlightRepositoryInterface) {
+}
+
+// fixedRepo answers every fetch with the same flights.
+func fixedRepo(providerName string, flights ...domain.Flight) *MockRepo {
+	return &MockRepo{ProviderName: providerName, FetchFunc: func(ctx context.Context) ([]domain.Flight, error) {
+		return flights, nil
+	}}
+}
+
+func fixedService(repositories ...repository.FlightRepositoryInterface) service.FlightService {
+	return service.NewFlightService(2, repositories...)
+}
+
+func testRouter(flightService service.FlightService) http.Handler {
+	return httpserver.NewRouter(handler.NewHealthHandler(), handler.NewFlightHandler(flightService), handler.NewAdminHandler("", nil))
+}
+
+func payloadConfig(t *testing.T, payload string, parsing string) config.ProviderConfig {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		_, _ = writer.Write([]byte(payload))
+	}))
+	t.Cleanup(server.Close)
+
+	providerConfig := serverConfig(t, server)
+	providerConfig.Parsing = parsing
+
+	return providerConfig
+}
+
+func serveRequest(httpHandler http.Handler, request *http.Request) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	httpHandler.ServeHTTP(recorder, request)
+
+	return recorder
+}
+
+// serveJSON decodes the body of a successful response into T.
+func serveJSON[T any](t *testing.T, httpHandler http.Handler, request *http.Request) (*httptest.ResponseRecorder, T) {
+	recorder := serveRequest(httpHandler, request)
+
+	var body T
+
+	if recorder.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	}
+
+	return recorder, body
+}
+
+func getJSON[T any](t *testing.T, httpHandler http.Handler, target string) (*httptest.ResponseRecorder, T) {
+	return serveJSON[T](t, httpHandler, httptest.NewRequest(http.MethodGet, target, nil))
+}
+
+func serveFlights(t *testing.T, flightService service.FlightService, target string) *httptest.ResponseRecorder {
+	return serveRequest(handler.NewFlightHandler(flightService), httptest.NewRequest(http.MethodGet, target, nil))
+}
+
+func TestHandlerParsesStopFilters(t *testing.T) {
+	flightService := &StubFlightService{}
+
+	recorder := serveFlights(t, flightService, "/flights?max_stops=1&direct_only=true&min_layover=45&max_layover=3h")
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	require.NotNil(t, flightService.LastQuery.MaxStops)
+	require.Equal(t, 1, *flightService.LastQuery.MaxStops)
+	require.True(t, flightService.LastQuery.DirectOnly)
+	require.Equal(t, 45*time.Minute, flightService.LastQuery.MinLayover)
+	require.Equal(t, 3*time.Hour, flightService.LastQuery.MaxLayover)
+}
+
+func TestHandlerRejectsMalformedStopFilters(t *testing.T) {
+	for _, target := range []string{
+		"/flights?max_stops=-1",
+		"/flights?max_stops=two",
+		"/flights?direct_only=maybe",
+		"/flights?min_layover=soon",
+	} {
+		recorder := serveFlights(t, &StubFlightService{}, target)
+		require.Equal(t, http.StatusBadRequest, recorder.Code, target)
+	}
+}
+
+func TestHandlerListsEveryInvalidParameter(t *testing.T) {
+	recorder := serveFlights(t, &StubFlightService{}, "/flights?from=cdg&to=HN1&sort=cheapest&order=up&mode=fast&max_stops=two&airline=A-F")
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Equal(t, problem.ContentType, recorder.Header().Get("Content-Type"))
+
+	var body struct {
+		Type          string                 `json:"type"`
+		Status        int                    `json:"status"`
+		InvalidParams []handler.InvalidParam `json:"invalid_params"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	require.Equal(t, problem.TypeInvalidParameters, body.Type)
+	require.Equal(t, http.StatusBadRequest, body.Status)
+
+	names := make([]string, 0, len(body.InvalidParams))
+
+	for _, invalidParam := range body.InvalidParams {
+		names = append(names, invalidParam.Name)
+	}
+
+	require.ElementsMatch(t, []string{"from", "to", "sort", "order", "mode", "max_stops", "airline"}, names)
+}
+
+func TestHandlerRejectsInvalidRanges(t *testing.T) {
+	for _, target := range []string{
+		"/flights?min_layover=3h&max_layover=1h",
+		"/flights?min_price=500&max_price=100",
+		"/flights?validation=loose",
+	} {
+		recorder := serveFlights(t, &StubFlightService{}, target)
+		require.Equal(t, http.StatusBadRequest, recorder.Code, target)
+	}
+}
+
+func TestHandlerLenientValidationFallsBack(t *testing.T) {
+	flightService := &StubFlightService{}
+
+	recorder := serveFlights(t, flightService, "/flights?validation=lenient&from=cdg&sort=cheapest&order=up&mode=fast")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "cdg", flightService.LastQuery.DepartureAirport)
+	require.Equal(t, sorter.SortByPrice, flightService.LastQuery.SortBy)
+	require.Equal(t, sorter.OrderAsc, flightService.LastQuery.SortOrder)
+	require.Equal(t, service.FetchModeDegraded, flightService.LastQuery.Mode)
+
+	recorder = serveFlights(t, flightService, "/flights?validation=lenient&max_stops=two")
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestHandlerLenientValidationKeepsConfiguredSort(t *testing.T) {
+	flightService := &StubFlightService{}
+	flightHandler := handler.NewFlightHandler(flightService)
+	flightHandler.SetDefaults(handler.SearchDefaults{SortBy: sorter.SortByTravelTime, SortOrder: sorter.OrderDesc, Validation: handler.ValidationLenient})
+
+	recorder := serveRequest(flightHandler, httptest.NewRequest(http.MethodGet, "/flights?sort=cheapest&order=up", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, sorter.SortByTravelTime, flightService.LastQuery.SortBy)
+	require.Equal(t, sorter.OrderDesc, flightService.LastQuery.SortOrder)
+}
+
+func TestHandlerRejectsDurationsAboveSevenDays(t *testing.T) {
+	recorder := serveFlights(t, &StubFlightService{}, "/flights?max_layover=99999999999&max_duration=169h&max_connection=10081&min_layover=10080")
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	var body struct {
+		InvalidParams []handler.InvalidParam `json:"invalid_params"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	require.ElementsMatch(t, []handler.InvalidParam{
+		{Name: "max_layover", Value: "99999999999", Reason: "must not exceed 7 days"},
+		{Name: "max_duration", Value: "169h", Reason: "must not exceed 7 days"},
+		{Name: "max_connection", Value: "10081", Reason: "must not exceed 7 days"},
+	}, body.InvalidParams)
+}
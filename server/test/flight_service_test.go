@@ -16,7 +16,12 @@ import (
 var _ repository.FlightRepositoryInterface = (*MockRepo)(nil)
 
 type MockRepo struct {
-	FetchFunc func(ctx context.Context) ([]domain.Flight, error)
+	ProviderName string
+	FetchFunc    func(ctx context.Context) ([]domain.Flight, error)
+}
+
+func (m *MockRepo) Name() string {
+	return m.ProviderName
 }
 
 func (m *MockRepo) Fetch(ctx context.Context) ([]domain.Flight, error) {
@@ -84,9 +89,16 @@ func TestFiltersAndSorts(t *testing.T) {
 
 	ctx := context.Background()
 
-	flights, err := svc.GetFlights(ctx, "CDG", "HND", sorter.SortByPrice, sorter.OrderAsc)
+	result, err := svc.GetFlights(ctx, service.FlightQuery{
+		DepartureAirport: "CDG",
+		ArrivalAirport:   "HND",
+		SortBy:           sorter.SortByPrice,
+		SortOrder:        sorter.OrderAsc,
+	})
 	require.NoError(t, err)
 
+	flights := result.Flights
+
 	require.Len(t, flights, 2)
 	require.Equal(t, "REF-1", flights[0].Reference)
 	require.Equal(t, float64(800), flights[0].Price)
@@ -138,8 +150,10 @@ func TestDedupPolicyCheapestThenEarliest(t *testing.T) {
 
 	svc := service.NewFlightService(3, repoA, repoB)
 
-	out, err := svc.GetFlights(context.Background(), "", "", sorter.SortByPrice, sorter.OrderAsc)
+	result, err := svc.GetFlights(context.Background(), service.FlightQuery{SortBy: sorter.SortByPrice, SortOrder: sorter.OrderAsc})
 	require.NoError(t, err)
+
+	out := result.Flights
 	require.Len(t, out, 1)
 	require.Equal(t, "DUP", out[0].Reference)
 	require.Equal(t, float64(100), out[0].Price)
@@ -163,7 +177,11 @@ func TestTimeoutErrorPropagates(t *testing.T) {
 	flightService := service.NewFlightService(1, blockingRepo, okRepo)
 
 	start := time.Now()
-	_, err := flightService.GetFlights(context.Background(), "", "", sorter.SortByPrice, sorter.OrderAsc)
+	_, err := flightService.GetFlights(context.Background(), service.FlightQuery{
+		SortBy:    sorter.SortByPrice,
+		SortOrder: sorter.OrderAsc,
+		Mode:      service.FetchModeStrict,
+	})
 	elapsed := time.Since(start)
 
 	require.Error(t, err)
@@ -171,3 +189,59 @@ func TestTimeoutErrorPropagates(t *testing.T) {
 
 	require.Less(t, elapsed, 3*time.Second, "timeout test ran too long")
 }
+
+func TestDegradedModeReturnsPartialResults(t *testing.T) {
+	failingRepo := &MockRepo{
+		ProviderName: "failing",
+		FetchFunc: func(ctx context.Context) ([]domain.Flight, error) {
+			return nil, errors.New("upstream unavailable")
+		},
+	}
+
+	okRepo := &MockRepo{
+		ProviderName: "healthy",
+		FetchFunc: func(ctx context.Context) ([]domain.Flight, error) {
+			return []domain.Flight{
+				{
+					Reference:     "OK-1",
+					From:          "CDG",
+					To:            "HND",
+					Price:         500,
+					DepartureTime: tTime(t, "2026-01-01T10:00:00Z"),
+					ArrivalTime:   tTime(t, "2026-01-01T20:00:00Z"),
+				},
+			}, nil
+		},
+	}
+
+	flightService := service.NewFlightService(1, failingRepo, okRepo)
+
+	result, err := flightService.GetFlights(context.Background(), service.FlightQuery{Mode: service.FetchModeDegraded})
+	require.NoError(t, err)
+	require.Len(t, result.Flights, 1)
+	require.Equal(t, "OK-1", result.Flights[0].Reference)
+
+	require.Len(t, result.Providers, 2)
+	require.Equal(t, "failing", result.Providers[0].Name)
+	require.Equal(t, service.ProviderStatusError, result.Providers[0].Status)
+	require.Equal(t, "provider error", result.Providers[0].Error)
+	require.Equal(t, "healthy", result.Providers[1].Name)
+	require.Equal(t, service.ProviderStatusOK, result.Providers[1].Status)
+
+	_, err = flightService.GetFlights(context.Background(), service.FlightQuery{Mode: service.FetchModeStrict})
+	require.Error(t, err)
+}
+
+func TestDegradedModeFailsWhenAllProvidersFail(t *testing.T) {
+	failingRepo := &MockRepo{
+		ProviderName: "failing",
+		FetchFunc: func(ctx context.Context) ([]domain.Flight, error) {
+			return nil, errors.New("upstream unavailable")
+		},
+	}
+
+	flightService := service.NewFlightService(1, failingRepo)
+
+	_, err := flightService.GetFlights(context.Background(), service.FlightQuery{})
+	require.Error(t, err)
+}
@@ -0,0 +1,153 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Orden14/flight-aggregator/src/domain"
+	"github.com/Orden14/flight-aggregator/src/handler"
+	"github.com/Orden14/flight-aggregator/src/service"
+	"github.com/Orden14/flight-aggregator/src/util/sorter"
+	"github.com/stretchr/testify/require"
+)
+
+type streamEvent struct {
+	Name string
+	Data map[string]any
+}
+
+func readStreamEvents(t *testing.T, body string) []streamEvent {
+	var events []streamEvent
+	var current streamEvent
+
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 1<<16), 1<<20)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			current.Name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &current.Data))
+		case line == "":
+			events = append(events, current)
+			current = streamEvent{}
+		}
+	}
+
+	return events
+}
+
+func streamFlight(t *testing.T, reference string, price float64) domain.Flight {
+	return domain.Flight{
+		Reference:     reference,
+		From:          "CDG",
+		To:            "HND",
+		Price:         price,
+		DepartureTime: tTime(t, "2026-01-01T10:00:00Z"),
+		ArrivalTime:   tTime(t, "2026-01-01T20:00:00Z"),
+	}
+}
+
+func TestStreamDeliversBatchesBeforeSlowProviders(t *testing.T) {
+	fastBatchSeen := make(chan struct{})
+
+	flightService := service.NewFlightService(2,
+		fixedRepo("fast", streamFlight(t, "F1", 500)),
+		&MockRepo{ProviderName: "slow", FetchFunc: func(ctx context.Context) ([]domain.Flight, error) {
+			select {
+			case <-fastBatchSeen:
+				return []domain.Flight{streamFlight(t, "S1", 300)}, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}},
+	)
+
+	var batches []service.ProviderBatch
+
+	result, err := flightService.StreamFlights(context.Background(), service.FlightQuery{SortBy: sorter.SortByPrice, SortOrder: sorter.OrderAsc}, func(batch service.ProviderBatch) {
+		batches = append(batches, batch)
+
+		if batch.Provider.Name == "fast" {
+			close(fastBatchSeen)
+		}
+	})
+	require.NoError(t, err)
+
+	require.Len(t, batches, 2)
+	require.Equal(t, "fast", batches[0].Provider.Name)
+	require.Equal(t, "slow", batches[1].Provider.Name)
+	require.Equal(t, "S1", batches[1].Flights[0].Reference)
+
+	require.Len(t, result.Flights, 2)
+	require.Equal(t, "S1", result.Flights[0].Reference)
+}
+
+func TestStreamHandlerEmitsProviderEventsThenSummary(t *testing.T) {
+	flightService := service.NewFlightService(1,
+		fixedRepo("ok", streamFlight(t, "A1", 500), streamFlight(t, "A2", 200)),
+		&MockRepo{ProviderName: "broken", FetchFunc: func(ctx context.Context) ([]domain.Flight, error) {
+			return nil, errors.New("boom")
+		}},
+	)
+
+	recorder := httptest.NewRecorder()
+	handler.NewFlightHandler(flightService).ServeStream(recorder, httptest.NewRequest(http.MethodGet, "/flights/stream", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "text/event-stream", recorder.Header().Get("Content-Type"))
+
+	events := readStreamEvents(t, recorder.Body.String())
+	require.Len(t, events, 3)
+
+	eventNames := []string{events[0].Name, events[1].Name}
+	require.ElementsMatch(t, []string{handler.StreamEventProvider, handler.StreamEventProviderError}, eventNames)
+
+	summary := events[2]
+	require.Equal(t, handler.StreamEventSummary, summary.Name)
+	require.EqualValues(t, 2, summary.Data["flights_count"])
+	require.Equal(t, "A2", summary.Data["items"].([]any)[0].(map[string]any)["reference"])
+}
+
+func TestStreamHandlerEndsWithErrorEventInStrictMode(t *testing.T) {
+	flightService := service.NewFlightService(1,
+		&MockRepo{ProviderName: "broken", FetchFunc: func(ctx context.Context) ([]domain.Flight, error) {
+			return nil, errors.New("boom")
+		}},
+	)
+
+	recorder := httptest.NewRecorder()
+	handler.NewFlightHandler(flightService).ServeStream(recorder, httptest.NewRequest(http.MethodGet, "/flights/stream?mode=strict", nil))
+
+	events := readStreamEvents(t, recorder.Body.String())
+	require.Len(t, events, 2)
+	require.Equal(t, handler.StreamEventProviderError, events[0].Name)
+	require.Equal(t, handler.StreamEventError, events[1].Name)
+	require.EqualValues(t, http.StatusBadGateway, events[1].Data["status"])
+	require.Equal(t, []any{"broken"}, events[1].Data["providers"])
+}
+
+func TestStreamHandlerRejectsInvalidQueryBeforeStreaming(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	handler.NewFlightHandler(&StubFlightService{}).ServeStream(recorder, httptest.NewRequest(http.MethodGet, "/flights/stream?from=cdg", nil))
+
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestStreamHandlerRejectsPaging(t *testing.T) {
+	recorder := serveRequest(http.HandlerFunc(handler.NewFlightHandler(&StubFlightService{}).ServeStream), httptest.NewRequest(http.MethodGet, "/flights/stream?limit=5&offset=10&cursor=abc", nil))
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	for _, name := range []string{"limit", "offset", "cursor"} {
+		require.Contains(t, recorder.Body.String(), `"name":"`+name+`"`)
+	}
+}
@@ -0,0 +1,112 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/domain"
+	"github.com/Orden14/flight-aggregator/src/service"
+	"github.com/Orden14/flight-aggregator/src/util/route"
+	"github.com/stretchr/testify/require"
+)
+
+func interlineFlights(t *testing.T) []domain.Flight {
+	return []domain.Flight{
+		routeFlight(t, "P1-AMS-CDG", "AMS", "CDG", "2026-01-01T06:00:00Z", "2026-01-01T07:15:00Z", 90),
+		routeFlight(t, "P1-AMS-HND", "AMS", "HND", "2026-01-01T09:00:00Z", "2026-01-02T01:00:00Z", 1400),
+		routeFlight(t, "P2-CDG-HND", "CDG", "HND", "2026-01-01T10:00:00Z", "2026-01-01T23:00:00Z", 950),
+		routeFlight(t, "P2-CDG-HND-TIGHT", "CDG", "HND", "2026-01-01T08:00:00Z", "2026-01-01T21:00:00Z", 800),
+		routeFlight(t, "P2-CDG-ICN", "CDG", "ICN", "2026-01-01T10:00:00Z", "2026-01-01T20:00:00Z", 600),
+		routeFlight(t, "P3-ICN-HND", "ICN", "HND", "2026-01-02T01:00:00Z", "2026-01-02T03:30:00Z", 150),
+	}
+}
+
+func flightReferences(flights []domain.Flight) []string {
+	references := make([]string, 0, len(flights))
+
+	for _, flight := range flights {
+		references = append(references, flight.Reference)
+	}
+
+	return references
+}
+
+func TestConnectChainsFlightsSoldSeparately(t *testing.T) {
+	itineraries := route.Connect(interlineFlights(t), []string{"AMS"}, []string{"HND"}, route.Constraints{MinConnection: 2 * time.Hour})
+	require.ElementsMatch(t, []string{"P1-AMS-CDG+P2-CDG-HND", "P1-AMS-CDG+P2-CDG-ICN+P3-ICN-HND"}, flightReferences(itineraries))
+
+	for _, itinerary := range itineraries {
+		require.True(t, itinerary.VirtualInterline)
+
+		if len(itinerary.Tickets) == 2 {
+			require.Equal(t, []string{"P1-AMS-CDG", "P2-CDG-HND"}, itinerary.Tickets)
+			require.Equal(t, 1040.0, itinerary.Price)
+			require.Equal(t, "CDG", itinerary.Segments[1].From)
+			require.Equal(t, 1, itinerary.StopCount())
+			require.Equal(t, 165, itinerary.ConnectionLayovers()[0].DurationMinutes)
+		}
+	}
+}
+
+func TestConnectAppliesStopAndDurationLimits(t *testing.T) {
+	maxStops := 1
+
+	itineraries := route.Connect(interlineFlights(t), []string{"AMS"}, []string{"HND"}, route.Constraints{MinConnection: 2 * time.Hour, MaxStops: &maxStops})
+	require.Equal(t, []string{"P1-AMS-CDG+P2-CDG-HND"}, flightReferences(itineraries))
+
+	itineraries = route.Connect(interlineFlights(t), []string{"AMS"}, []string{"HND"}, route.Constraints{MinConnection: 30 * time.Minute, MaxDuration: 16 * time.Hour})
+	require.Equal(t, []string{"P1-AMS-CDG+P2-CDG-HND-TIGHT"}, flightReferences(itineraries))
+
+	itineraries = route.Connect(interlineFlights(t), []string{"AMS"}, []string{"HND"}, route.Constraints{MinConnection: 30 * time.Minute, MaxConnection: time.Hour})
+	require.Equal(t, []string{"P1-AMS-CDG+P2-CDG-HND-TIGHT"}, flightReferences(itineraries))
+}
+
+func TestFlightsIncludeVirtualInterlineWhenRequested(t *testing.T) {
+	flights := interlineFlights(t)
+
+	flightService := service.NewFlightService(2,
+		fixedRepo("provider-1", flights[:2]...),
+		fixedRepo("provider-2", flights[2:]...),
+	)
+
+	recorder := serveFlights(t, flightService, "/flights?from=AMS&to=HND&interline=true&max_stops=1")
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var body struct {
+		Items []domain.Flight `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	require.Equal(t, []string{"P1-AMS-CDG+P2-CDG-HND", "P1-AMS-HND"}, flightReferences(body.Items))
+	require.True(t, body.Items[0].VirtualInterline)
+	require.False(t, body.Items[1].VirtualInterline)
+
+	recorder = serveFlights(t, flightService, "/flights?from=AMS&to=HND")
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	require.Equal(t, []string{"P1-AMS-HND"}, flightReferences(body.Items))
+}
+
+func TestFlightDetailResolvesInterlineReferences(t *testing.T) {
+	flights := interlineFlights(t)
+
+	flightService := service.NewFlightService(2,
+		fixedRepo("provider-1", flights[:2]...),
+		fixedRepo("provider-2", flights[2:]...),
+	)
+
+	recorder, body := getJSON[detailBody](t, testRouter(flightService), "/flights/P1-AMS-CDG+P2-CDG-HND")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.True(t, body.Flight.VirtualInterline)
+	require.Equal(t, 1040.0, body.Flight.Price)
+	require.Equal(t, []string{"provider-1", "provider-2"}, body.OfferedBy)
+
+	recorder, _ = getJSON[detailBody](t, testRouter(flightService), "/flights/P2-CDG-HND+P1-AMS-CDG")
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestInterlineRequiresBothAirports(t *testing.T) {
+	recorder := serveFlights(t, service.NewFlightService(2), "/flights?from=AMS&interline=true")
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `"name":"interline"`)
+}
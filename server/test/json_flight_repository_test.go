@@ -0,0 +1,180 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/Orden14/flight-aggregator/src/config"
+	"github.com/Orden14/flight-aggregator/src/repository"
+	"github.com/stretchr/testify/require"
+)
+
+var server1Mapping = config.FieldMappingConfig{
+	Reference:     "/bookingId",
+	FlightNumber:  "/flightNumber",
+	From:          "/departureAirport",
+	To:            "/arrivalAirport",
+	DepartureTime: "/departureTime",
+	ArrivalTime:   "/arrivalTime",
+	Price:         "/price",
+	Currency:      "/currency",
+}
+
+var server2Mapping = config.FieldMappingConfig{
+	Reference:     "/reference",
+	Segments:      "/segments",
+	FlightNumber:  "/flight/number",
+	From:          "/flight/from",
+	To:            "/flight/to",
+	DepartureTime: "/flight/depart",
+	ArrivalTime:   "/flight/arrive",
+	Price:         "/total/amount",
+	Currency:      "/total/currency",
+}
+
+func jsonServerFromDB(t *testing.T, dbPath string) *httptest.Server {
+	content, err := os.ReadFile(dbPath)
+	require.NoError(t, err)
+
+	var collections map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(content, &collections))
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		collection, isPresent := collections[request.URL.Path[1:]]
+
+		if !isPresent {
+			http.NotFound(writer, request)
+
+			return
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		_, _ = writer.Write(collection)
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestJSONRepositoryMatchesServer1Repository(t *testing.T) {
+	server := jsonServerFromDB(t, "../../j-server1/db.json")
+	serverConfig := serverConfig(t, server)
+
+	expected, err := repository.NewServer1FlightRepository(serverConfig, testRetryPolicy(1)).Fetch(context.Background())
+	require.NoError(t, err)
+
+	serverConfig.Path = "flights"
+	serverConfig.Mapping = server1Mapping
+
+	jsonRepository, err := repository.NewJSONFlightRepository(serverConfig, testRetryPolicy(1))
+	require.NoError(t, err)
+
+	actual, err := jsonRepository.Fetch(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, actual)
+	require.Equal(t, expected, actual)
+}
+
+func TestJSONRepositoryMatchesServer2Repository(t *testing.T) {
+	server := jsonServerFromDB(t, "../../j-server2/db.json")
+	serverConfig := serverConfig(t, server)
+
+	expected, err := repository.NewServer2FlightRepository(serverConfig, testRetryPolicy(1)).Fetch(context.Background())
+	require.NoError(t, err)
+
+	serverConfig.Path = "/flight_to_book"
+	serverConfig.Mapping = server2Mapping
+
+	jsonRepository, err := repository.NewJSONFlightRepository(serverConfig, testRetryPolicy(1))
+	require.NoError(t, err)
+
+	actual, err := jsonRepository.Fetch(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, actual)
+	require.Equal(t, expected, actual)
+}
+
+func TestJSONRepositoryReadsNestedRecords(t *testing.T) {
+	providerConfig := payloadConfig(t, `{"data": {"offers": [{
+		"id": "C1",
+		"leg": {"code": "LH1", "origin": "FRA", "destination": "CDG", "out": "2026-01-01T08:00:00Z", "in": "2026-01-01T09:15:00Z"},
+		"fare": {"value": "199.90", "ccy": "EUR"}
+	}]}}`, config.ParsingLenient)
+	providerConfig.Path = "offers"
+	providerConfig.Mapping = config.FieldMappingConfig{
+		Records:       "/data/offers",
+		Reference:     "/id",
+		FlightNumber:  "/leg/code",
+		From:          "/leg/origin",
+		To:            "/leg/destination",
+		DepartureTime: "/leg/out",
+		ArrivalTime:   "/leg/in",
+		Price:         "/fare/value",
+		Currency:      "/fare/ccy",
+	}
+
+	jsonRepository, err := repository.NewJSONFlightRepository(providerConfig, testRetryPolicy(1))
+	require.NoError(t, err)
+
+	flights, err := jsonRepository.Fetch(context.Background())
+	require.NoError(t, err)
+	require.Len(t, flights, 1)
+	require.Equal(t, "C1", flights[0].Reference)
+	require.Equal(t, "FRA", flights[0].From)
+	require.Equal(t, 199.90, flights[0].Price)
+}
+
+func TestJSONRepositoryRejectsIncompleteMapping(t *testing.T) {
+	_, err := repository.NewJSONFlightRepository(config.ProviderConfig{
+		Name:    "broken",
+		BaseURL: "http://localhost:1",
+		Mapping: config.FieldMappingConfig{Reference: "/id"},
+	}, testRetryPolicy(1))
+	require.EqualError(t, err, "provider broken: field mapping: missing flight_number")
+}
+
+func TestJSONRepositoryRejectsMalformedPointers(t *testing.T) {
+	for field, mapping := range map[string]config.FieldMappingConfig{
+		"records":   withMapping(func(mapping *config.FieldMappingConfig) { mapping.Records = "data" }),
+		"segments":  withMapping(func(mapping *config.FieldMappingConfig) { mapping.Segments = "/legs~2" }),
+		"price":     withMapping(func(mapping *config.FieldMappingConfig) { mapping.Price = "price" }),
+		"reference": withMapping(func(mapping *config.FieldMappingConfig) { mapping.Reference = "/id~" }),
+	} {
+		_, err := repository.NewJSONFlightRepository(config.ProviderConfig{Name: "broken", BaseURL: "http://localhost:1", Mapping: mapping}, testRetryPolicy(1))
+		require.ErrorContains(t, err, "field mapping: "+field+":", field)
+	}
+}
+
+func withMapping(change func(mapping *config.FieldMappingConfig)) config.FieldMappingConfig {
+	mapping := server1Mapping
+	change(&mapping)
+
+	return mapping
+}
+
+func TestProviderRepositorySendsAuthHeader(t *testing.T) {
+	var authorization string
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		authorization = request.Header.Get("Authorization")
+		_, _ = writer.Write([]byte(server1Payload))
+	}))
+	t.Cleanup(server.Close)
+
+	providerConfig := serverConfig(t, server)
+	providerConfig.Type = config.ProviderTypeServer1
+	providerConfig.Auth = config.AuthConfig{Type: config.AuthTypeBearer, Token: "secret"}
+
+	flightRepository, err := repository.NewProviderRepository(providerConfig, testRetryPolicy(1))
+	require.NoError(t, err)
+
+	flights, err := flightRepository.Fetch(context.Background())
+	require.NoError(t, err)
+	require.Len(t, flights, 1)
+	require.Equal(t, "Bearer secret", authorization)
+}
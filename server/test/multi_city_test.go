@@ -0,0 +1,131 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Orden14/flight-aggregator/src/domain"
+	"github.com/Orden14/flight-aggregator/src/service"
+	"github.com/stretchr/testify/require"
+)
+
+func multiCityService(t *testing.T) service.FlightService {
+	return fixedService(
+		fixedRepo("provider-1",
+			routeFlight(t, "CH-SLOW", "CDG", "HND", "2026-01-01T10:00:00Z", "2026-01-02T04:00:00Z", 700),
+			routeFlight(t, "CH-FAST", "CDG", "HND", "2026-01-01T11:00:00Z", "2026-01-02T00:00:00Z", 1000),
+			routeFlight(t, "HI-1", "HND", "ICN", "2026-01-04T09:00:00Z", "2026-01-04T11:30:00Z", 200),
+		),
+		fixedRepo("provider-2",
+			routeFlight(t, "HI-EARLY", "HND", "ICN", "2026-01-01T20:00:00Z", "2026-01-01T22:30:00Z", 50),
+			routeFlight(t, "IC-1", "ICN", "CDG", "2026-01-07T10:00:00Z", "2026-01-07T23:00:00Z", 500),
+			routeFlight(t, "IC-2", "ICN", "CDG", "2026-01-08T10:00:00Z", "2026-01-08T22:00:00Z", 650),
+		),
+	)
+}
+
+func postMultiCity(t *testing.T, body string) (*httptest.ResponseRecorder, roundTripBody) {
+	return serveJSON[roundTripBody](t, testRouter(multiCityService(t)), httptest.NewRequest(http.MethodPost, "/flights/multi-city", strings.NewReader(body)))
+}
+
+const multiCityLegs = `[
+	{"from": "CDG", "to": "HND", "departure_date": "2026-01-01"},
+	{"from": "HND", "to": "ICN"},
+	{"from": "ICN", "to": "CDG", "departure_date_from": "2026-01-05", "departure_date_to": "2026-01-10"}
+]`
+
+func TestMultiCityFindsCheapestChronologicalCombinations(t *testing.T) {
+	recorder, body := postMultiCity(t, `{"legs": `+multiCityLegs+`}`)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, []string{"CH-SLOW+HI-1+IC-1", "CH-SLOW+HI-1+IC-2", "CH-FAST+HI-1+IC-1", "CH-FAST+HI-1+IC-2"}, itineraryReferences(body.Items))
+
+	cheapest := body.Items[0]
+	require.Equal(t, 1400.0, cheapest.Price)
+	require.Equal(t, "CDG", cheapest.From)
+	require.Equal(t, "CDG", cheapest.To)
+	require.Len(t, cheapest.Flights, 3)
+}
+
+func TestMultiCityMinimisesTravelTimeWithinLimit(t *testing.T) {
+	_, body := postMultiCity(t, `{"legs": `+multiCityLegs+`, "objective": "travel_time", "limit": 1}`)
+	require.Equal(t, []string{"CH-FAST+HI-1+IC-2"}, itineraryReferences(body.Items))
+}
+
+func TestMultiCityAppliesSharedFilters(t *testing.T) {
+	_, body := postMultiCity(t, `{"legs": `+multiCityLegs+`, "max_price": 600}`)
+	require.Equal(t, []string{}, itineraryReferences(body.Items))
+
+	_, body = postMultiCity(t, `{"legs": [
+		{"from": "CDG", "to": "HND", "max_price": 800},
+		{"from": "HND", "to": "ICN"},
+		{"from": "ICN", "to": "CDG"}
+	], "limit": 2}`)
+	require.Equal(t, []string{"CH-SLOW+HI-1+IC-1", "CH-SLOW+HI-1+IC-2"}, itineraryReferences(body.Items))
+}
+
+func TestMultiCityReportsInvalidLegs(t *testing.T) {
+	recorder, _ := postMultiCity(t, `{"legs": [{"from": "CDG", "to": "hnd"}, {"from": "HND", "sort": "price"}], "objective": "departure_date"}`)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	for _, name := range []string{"legs[0].to", "legs[1].to", "legs[1].sort", "objective"} {
+		require.Contains(t, recorder.Body.String(), `"name":"`+name+`"`)
+	}
+
+	recorder, _ = postMultiCity(t, `not json`)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestMultiCityOnlyAcceptsPost(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	testRouter(multiCityService(t)).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/flights/multi-city", nil))
+	require.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+	require.Equal(t, http.MethodPost, recorder.Header().Get("Allow"))
+}
+
+func TestMultiCityStopsAtTheSearchBudget(t *testing.T) {
+	var flights []domain.Flight
+	var legs []string
+
+	for legIndex := range 6 {
+		from, to := "CDG", "HND"
+
+		if legIndex%2 == 1 {
+			from, to = to, from
+		}
+
+		date := fmt.Sprintf("2026-01-%02d", 1+2*legIndex)
+		legs = append(legs, fmt.Sprintf(`{"from": %q, "to": %q, "departure_date": %q}`, from, to, date))
+
+		for flightIndex := range 10 {
+			departure := fmt.Sprintf("%sT%02d:00:00Z", date, flightIndex)
+			arrival := fmt.Sprintf("%sT%02d:30:00Z", date, flightIndex)
+			flights = append(flights, routeFlight(t, fmt.Sprintf("L%dF%d", legIndex, flightIndex), from, to, departure, arrival, 100))
+		}
+	}
+
+	flightService := service.NewFlightService(1, fixedRepo("provider", flights...))
+
+	recorder := httptest.NewRecorder()
+	body := `{"legs": [` + strings.Join(legs, ",") + `]}`
+	testRouter(flightService).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/flights/multi-city", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Truncated bool               `json:"truncated"`
+		Items     []domain.Itinerary `json:"items"`
+	}
+
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.True(t, response.Truncated)
+	require.Len(t, response.Items, 10)
+}
+
+func TestMultiCityRejectsOversizedBody(t *testing.T) {
+	recorder, _ := postMultiCity(t, `{"legs": `+multiCityLegs+`, "airline": "`+strings.Repeat("A", 70<<10)+`"}`)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "must not exceed")
+}
@@ -0,0 +1,118 @@
+package test
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/domain"
+	"github.com/Orden14/flight-aggregator/src/handler"
+	"github.com/Orden14/flight-aggregator/src/problem"
+	"github.com/Orden14/flight-aggregator/src/service"
+	"github.com/stretchr/testify/require"
+)
+
+func formatService(t *testing.T) *StubFlightService {
+	connection := connectingFlight(t, "B1", []string{"FRA"}, []time.Duration{90 * time.Minute})
+	connection.Stops = connection.StopCount()
+	connection.Layovers = connection.ConnectionLayovers()
+
+	return &StubFlightService{Result: &service.FlightSearchResult{Flights: []domain.Flight{
+		{
+			Reference:     "A1",
+			FlightNumber:  "JL046",
+			From:          "CDG",
+			To:            "HND",
+			DepartureTime: tTime(t, "2026-01-01T13:00:00Z"),
+			ArrivalTime:   tTime(t, "2026-01-02T08:30:00Z"),
+			Price:         850.5,
+			Currency:      "EUR",
+		},
+		connection,
+	}, Providers: []service.ProviderStatus{
+		{Name: "j-server1", Status: service.ProviderStatusOK},
+		{Name: "j-server2", Status: service.ProviderStatusError, ErrorCategory: "timeout"},
+	}}}
+}
+
+func serveFlightsAccepting(t *testing.T, flightService service.FlightService, target string, accept string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, target, nil)
+	request.Header.Set("Accept", accept)
+
+	return serveRequest(handler.NewFlightHandler(flightService), request)
+}
+
+func TestFlightsAsCSV(t *testing.T) {
+	recorder := serveFlightsAccepting(t, formatService(t), "/flights", "text/csv")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.True(t, strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/csv"))
+
+	records, err := csv.NewReader(recorder.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	require.Equal(t, []string{"reference", "flightNumber", "from", "to", "departureTime", "arrivalTime", "price", "currency", "travelTimeMinutes", "stops", "segments", "layovers", "virtualInterline", "tickets"}, records[0])
+	require.Equal(t, "A1", records[1][0])
+	require.Equal(t, "2026-01-01T13:00:00Z", records[1][4])
+	require.Equal(t, "850.5", records[1][6])
+	require.Equal(t, "1", records[2][9])
+	require.Contains(t, records[2][10], "|")
+	require.Equal(t, "false", records[1][12])
+	require.Equal(t, "j-server1=ok, j-server2=error; category=timeout", recorder.Header().Get("X-Provider-Status"))
+}
+
+func TestCSVEscapesFormulaCells(t *testing.T) {
+	flightService := &StubFlightService{Result: &service.FlightSearchResult{Flights: []domain.Flight{
+		{Reference: "=HYPERLINK(\"http://evil\")", FlightNumber: "@SUM(A1)", From: "+CDG", To: "-HND", Currency: "EUR"},
+	}}}
+
+	records, err := csv.NewReader(serveFlights(t, flightService, "/flights?format=csv").Body).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, []string{"'=HYPERLINK(\"http://evil\")", "'@SUM(A1)", "'+CDG", "'-HND"}, records[1][:4])
+	require.Equal(t, "EUR", records[1][7])
+}
+
+func TestFlightsAsNDJSON(t *testing.T) {
+	recorder := serveFlights(t, formatService(t), "/flights?format=ndjson")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "application/x-ndjson", recorder.Header().Get("Content-Type"))
+
+	lines := strings.Split(strings.TrimSpace(recorder.Body.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var flight domain.Flight
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &flight))
+	require.Equal(t, "A1", flight.Reference)
+	require.Equal(t, "j-server1=ok, j-server2=error; category=timeout", recorder.Header().Get("X-Provider-Status"))
+}
+
+func TestFormatParameterWinsOverAccept(t *testing.T) {
+	recorder := serveFlightsAccepting(t, formatService(t), "/flights?format=json", "text/csv")
+	require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+}
+
+func TestAcceptNegotiation(t *testing.T) {
+	for accept, contentType := range map[string]string{
+		"": "application/json",
+		"text/html,application/xhtml+xml,*/*;q=0.8": "application/json",
+		"application/x-ndjson;q=0.5, text/csv":      "text/csv; charset=utf-8",
+		"text/csv;q=0, application/ndjson":          "application/x-ndjson",
+	} {
+		recorder := serveFlightsAccepting(t, formatService(t), "/flights", accept)
+		require.Equal(t, http.StatusOK, recorder.Code, accept)
+		require.Equal(t, contentType, recorder.Header().Get("Content-Type"), accept)
+	}
+}
+
+func TestUnsupportedFormatsAreRejected(t *testing.T) {
+	recorder := serveFlightsAccepting(t, formatService(t), "/flights", "application/xml")
+	require.Equal(t, http.StatusNotAcceptable, recorder.Code)
+	require.Equal(t, problem.ContentType, recorder.Header().Get("Content-Type"))
+
+	recorder = serveFlights(t, formatService(t), "/flights?format=xlsx")
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "format")
+}
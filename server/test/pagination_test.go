@@ -0,0 +1,144 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/domain"
+	"github.com/Orden14/flight-aggregator/src/handler"
+	"github.com/Orden14/flight-aggregator/src/service"
+	"github.com/stretchr/testify/require"
+)
+
+type pageBody struct {
+	FlightsCount int             `json:"flights_count"`
+	Total        int             `json:"total"`
+	Offset       int             `json:"offset"`
+	Next         *string         `json:"next"`
+	Prev         *string         `json:"prev"`
+	Items        []domain.Flight `json:"items"`
+}
+
+func paginatedHandler(t *testing.T, cacheTTL time.Duration) *handler.FlightHandler {
+	flights := []domain.Flight{
+		streamFlight(t, "P1", 500),
+		streamFlight(t, "P2", 300),
+		streamFlight(t, "P3", 300),
+		streamFlight(t, "P4", 700),
+		streamFlight(t, "P5", 100),
+	}
+
+	flightService := service.NewFlightServiceWithOptions(service.FlightServiceOptions{CacheTTL: cacheTTL}, fixedRepo("paged", flights...))
+
+	return handler.NewFlightHandler(flightService)
+}
+
+func TestCursorPaginationWalksForwardAndBack(t *testing.T) {
+	flightHandler := paginatedHandler(t, time.Minute)
+
+	recorder, body := getJSON[pageBody](t, flightHandler, "/flights?from=CDG&limit=2")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "5", recorder.Header().Get("X-Total-Count"))
+	require.Equal(t, 5, body.Total)
+	require.Nil(t, body.Prev)
+	require.Equal(t, []string{"P5", "P2"}, flightReferences(body.Items))
+
+	var seen []string
+	seen = append(seen, flightReferences(body.Items)...)
+
+	for body.Next != nil {
+		recorder, body = getJSON[pageBody](t, flightHandler, *body.Next)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		seen = append(seen, flightReferences(body.Items)...)
+	}
+
+	require.Equal(t, []string{"P5", "P2", "P3", "P1", "P4"}, seen)
+	require.Equal(t, []string{"P4"}, flightReferences(body.Items))
+
+	require.NotNil(t, body.Prev)
+	_, body = getJSON[pageBody](t, flightHandler, *body.Prev)
+	require.Equal(t, []string{"P3", "P1"}, flightReferences(body.Items))
+	require.Equal(t, 2, body.Offset)
+}
+
+func TestOffsetPagination(t *testing.T) {
+	recorder, body := getJSON[pageBody](t, paginatedHandler(t, time.Minute), "/flights?limit=2&offset=2&sort=price&order=desc")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, []string{"P3", "P2"}, flightReferences(body.Items))
+	require.Contains(t, *body.Next, "offset=4")
+	require.Contains(t, *body.Prev, "offset=0")
+	require.Len(t, recorder.Header().Values("Link"), 2)
+}
+
+func TestWithoutLimitEveryFlightIsReturned(t *testing.T) {
+	_, body := getJSON[pageBody](t, paginatedHandler(t, time.Minute), "/flights")
+	require.Len(t, body.Items, 5)
+	require.Equal(t, 5, body.Total)
+	require.Nil(t, body.Next)
+}
+
+func TestInvalidPaginationParameters(t *testing.T) {
+	flightHandler := paginatedHandler(t, time.Minute)
+
+	_, body := getJSON[pageBody](t, flightHandler, "/flights?from=CDG&limit=2")
+	require.NotNil(t, body.Next)
+
+	nextURL, err := url.Parse(*body.Next)
+	require.NoError(t, err)
+
+	cursor := url.QueryEscape(nextURL.Query().Get("cursor"))
+
+	for _, target := range []string{
+		"/flights?limit=0",
+		"/flights?limit=1000",
+		"/flights?offset=-1",
+		"/flights?cursor=not-a-cursor",
+		"/flights?from=CDG&limit=2&offset=2&cursor=" + cursor,
+		"/flights?from=HND&limit=2&cursor=" + cursor,
+		"/flights?from=CDG&limit=2&order=desc&cursor=" + cursor,
+	} {
+		recorder, _ := getJSON[pageBody](t, flightHandler, target)
+		require.Equal(t, http.StatusBadRequest, recorder.Code, target)
+	}
+}
+
+func TestCursorIsRejectedOnceTheSnapshotChanges(t *testing.T) {
+	flightHandler := paginatedHandler(t, time.Millisecond)
+
+	_, body := getJSON[pageBody](t, flightHandler, "/flights?limit=2")
+	require.NotNil(t, body.Next)
+
+	time.Sleep(5 * time.Millisecond)
+
+	recorder, _ := getJSON[pageBody](t, flightHandler, *body.Next)
+	require.Equal(t, http.StatusConflict, recorder.Code)
+}
+
+func TestCursorIsRejectedWithoutCacheOnceProviderDataChanges(t *testing.T) {
+	flights := []domain.Flight{
+		streamFlight(t, "P1", 500),
+		streamFlight(t, "P2", 300),
+		streamFlight(t, "P3", 100),
+	}
+
+	flightService := service.NewFlightServiceWithOptions(service.FlightServiceOptions{},
+		&MockRepo{ProviderName: "paged", FetchFunc: func(ctx context.Context) ([]domain.Flight, error) {
+			return flights, nil
+		}},
+	)
+	flightHandler := handler.NewFlightHandler(flightService)
+
+	_, body := getJSON[pageBody](t, flightHandler, "/flights?limit=2")
+	require.NotNil(t, body.Next)
+
+	recorder, _ := getJSON[pageBody](t, flightHandler, *body.Next)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	flights = append(flights, streamFlight(t, "P4", 200))
+
+	recorder, _ = getJSON[pageBody](t, flightHandler, *body.Next)
+	require.Equal(t, http.StatusConflict, recorder.Code)
+}
@@ -0,0 +1,126 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Orden14/flight-aggregator/src/domain"
+	"github.com/Orden14/flight-aggregator/src/service"
+	"github.com/stretchr/testify/require"
+)
+
+type calendarBody struct {
+	Days  []service.CalendarDay  `json:"days"`
+	Pairs []service.CalendarPair `json:"pairs"`
+}
+
+func calendarService(t *testing.T, fetchCount *atomic.Int32) service.FlightService {
+	return service.NewFlightService(2,
+		&MockRepo{ProviderName: "provider-1", FetchFunc: func(ctx context.Context) ([]domain.Flight, error) {
+			fetchCount.Add(1)
+
+			return []domain.Flight{
+				routeFlight(t, "J1", "CDG", "HND", "2026-01-01T10:00:00Z", "2026-01-01T23:00:00Z", 900),
+				routeFlight(t, "J1-LATE", "CDG", "HND", "2026-01-01T20:00:00Z", "2026-01-02T09:00:00Z", 700),
+				routeFlight(t, "J31", "CDG", "HND", "2026-01-31T10:00:00Z", "2026-01-31T23:00:00Z", 650),
+				routeFlight(t, "F1", "CDG", "HND", "2026-02-01T10:00:00Z", "2026-02-01T23:00:00Z", 100),
+				routeFlight(t, "R5", "HND", "CDG", "2026-01-05T10:00:00Z", "2026-01-05T22:00:00Z", 300),
+			}, nil
+		}},
+		&MockRepo{ProviderName: "provider-2", FetchFunc: func(ctx context.Context) ([]domain.Flight, error) {
+			fetchCount.Add(1)
+
+			return []domain.Flight{
+				routeFlight(t, "J1", "CDG", "HND", "2026-01-01T10:00:00Z", "2026-01-01T23:00:00Z", 600),
+				routeFlight(t, "R5-CHEAP", "HND", "CDG", "2026-01-05T14:00:00Z", "2026-01-06T02:00:00Z", 250),
+				routeFlight(t, "R7", "HND", "CDG", "2026-01-07T10:00:00Z", "2026-01-07T22:00:00Z", 200),
+			}, nil
+		}},
+	)
+}
+
+func TestPriceCalendarGivesCheapestPricePerDay(t *testing.T) {
+	var fetchCount atomic.Int32
+
+	recorder, body := getJSON[calendarBody](t, testRouter(calendarService(t, &fetchCount)), "/flights/calendar?from=CDG&to=HND&month=2026-01")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, int32(2), fetchCount.Load())
+	require.Len(t, body.Days, 31)
+
+	firstDay := body.Days[0]
+	require.Equal(t, "2026-01-01", firstDay.Date)
+	require.Equal(t, 600.0, *firstDay.Price)
+	require.Equal(t, "J1", firstDay.Reference)
+	require.Equal(t, 2, firstDay.FlightsCount)
+
+	require.Nil(t, body.Days[1].Price)
+	require.Equal(t, 0, body.Days[1].FlightsCount)
+	require.Equal(t, 650.0, *body.Days[30].Price)
+	require.Nil(t, body.Pairs)
+}
+
+func TestPriceCalendarPairsReturnDays(t *testing.T) {
+	var fetchCount atomic.Int32
+
+	_, body := getJSON[calendarBody](t, testRouter(calendarService(t, &fetchCount)), "/flights/calendar?from=CDG&to=HND&month=2026-01&round_trip=true&max_stay=6")
+	require.Equal(t, []service.CalendarPair{
+		{DepartureDate: "2026-01-01", ReturnDate: "2026-01-05", Price: 850, Currency: "EUR", Reference: "J1+R5-CHEAP"},
+		{DepartureDate: "2026-01-01", ReturnDate: "2026-01-07", Price: 800, Currency: "EUR", Reference: "J1+R7"},
+	}, body.Pairs)
+}
+
+func TestPriceCalendarValidatesMonth(t *testing.T) {
+	var fetchCount atomic.Int32
+
+	recorder, _ := getJSON[calendarBody](t, testRouter(calendarService(t, &fetchCount)), "/flights/calendar?from=CDG&month=january&departure_date=2026-01-01")
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	for _, name := range []string{"month", "to", "departure_date"} {
+		require.Contains(t, recorder.Body.String(), `"name":"`+name+`"`)
+	}
+
+	require.Equal(t, int32(0), fetchCount.Load())
+}
+
+func TestPriceCalendarAppliesPriceBoundsToOneWayDays(t *testing.T) {
+	var fetchCount atomic.Int32
+
+	_, body := getJSON[calendarBody](t, testRouter(calendarService(t, &fetchCount)), "/flights/calendar?from=CDG&to=HND&month=2026-01&max_price=620")
+	require.Equal(t, 600.0, *body.Days[0].Price)
+	require.Equal(t, 1, body.Days[0].FlightsCount)
+	require.Nil(t, body.Days[30].Price)
+}
+
+func TestPriceCalendarKeepsCurrenciesApart(t *testing.T) {
+	flightService := service.NewFlightService(1,
+		&MockRepo{ProviderName: "provider", FetchFunc: func(ctx context.Context) ([]domain.Flight, error) {
+			dollarFlight := routeFlight(t, "USD1", "CDG", "HND", "2026-01-01T12:00:00Z", "2026-01-02T01:00:00Z", 500)
+			dollarFlight.Currency = "USD"
+
+			return []domain.Flight{
+				routeFlight(t, "EUR1", "CDG", "HND", "2026-01-01T10:00:00Z", "2026-01-01T23:00:00Z", 900),
+				dollarFlight,
+			}, nil
+		}},
+	)
+
+	_, body := getJSON[calendarBody](t, testRouter(flightService), "/flights/calendar?from=CDG&to=HND&month=2026-01")
+	require.Len(t, body.Days, 32)
+	require.Equal(t, "2026-01-01", body.Days[0].Date)
+	require.Equal(t, "EUR1", body.Days[0].Reference)
+	require.Equal(t, "2026-01-01", body.Days[1].Date)
+	require.Equal(t, "USD1", body.Days[1].Reference)
+	require.Equal(t, "2026-01-02", body.Days[2].Date)
+}
+
+func TestPriceCalendarRejectsCursor(t *testing.T) {
+	var fetchCount atomic.Int32
+
+	recorder, _ := getJSON[calendarBody](t, testRouter(calendarService(t, &fetchCount)), "/flights/calendar?from=CDG&to=HND&month=2026-01&cursor=abc&interline=true")
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "is not supported by the price calendar")
+	require.NotContains(t, recorder.Body.String(), "limit and offset")
+	require.Contains(t, recorder.Body.String(), `"name":"interline"`)
+}
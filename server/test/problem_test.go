@@ -0,0 +1,81 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Orden14/flight-aggregator/src/domain"
+	"github.com/Orden14/flight-aggregator/src/handler"
+	"github.com/Orden14/flight-aggregator/src/httpserver"
+	"github.com/Orden14/flight-aggregator/src/problem"
+	"github.com/Orden14/flight-aggregator/src/repository"
+	"github.com/Orden14/flight-aggregator/src/service"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeProblem(t *testing.T, recorder *httptest.ResponseRecorder) map[string]any {
+	require.Equal(t, problem.ContentType, recorder.Header().Get("Content-Type"))
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+
+	return body
+}
+
+func TestSearchFailuresMapToProblems(t *testing.T) {
+	for _, testCase := range []struct {
+		err         error
+		status      int
+		problemType string
+	}{
+		{fmt.Errorf("flight GET http://internal:4001/flights: %w: %w", repository.ErrTimeout, context.DeadlineExceeded), http.StatusGatewayTimeout, problem.TypeProviderTimeout},
+		{fmt.Errorf("%w 500: stack trace", repository.ErrBadStatus), http.StatusBadGateway, problem.TypeProviderError},
+		{fmt.Errorf("%w array: unexpected EOF", repository.ErrDecode), http.StatusBadGateway, problem.TypeProviderError},
+		{fmt.Errorf("server1: %w", repository.ErrCircuitOpen), http.StatusServiceUnavailable, problem.TypeProvidersUnavailable},
+	} {
+		flightService := service.NewFlightService(1,
+			&MockRepo{ProviderName: "server1", FetchFunc: func(ctx context.Context) ([]domain.Flight, error) { return nil, testCase.err }},
+		)
+
+		recorder := serveFlights(t, flightService, "/flights")
+		require.Equal(t, testCase.status, recorder.Code, testCase.err.Error())
+
+		body := decodeProblem(t, recorder)
+		require.Equal(t, testCase.problemType, body["type"])
+		require.EqualValues(t, testCase.status, body["status"])
+		require.Equal(t, "/flights", body["instance"])
+		require.NotEmpty(t, body["request_id"])
+		require.Equal(t, []any{"server1"}, body["providers"])
+		require.NotContains(t, recorder.Body.String(), "internal:4001")
+		require.NotContains(t, recorder.Body.String(), "stack trace")
+	}
+}
+
+func TestProblemEchoesRequestID(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/flights", nil)
+	request.Header.Set(problem.RequestIDHeader, "abc-123")
+
+	handler.NewFlightHandler(&StubFlightService{Err: errors.New("boom")}).ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusBadGateway, recorder.Code)
+	require.Equal(t, "abc-123", recorder.Header().Get(problem.RequestIDHeader))
+	require.Equal(t, "abc-123", decodeProblem(t, recorder)["request_id"])
+}
+
+func TestRouterRendersMethodNotAllowedAsProblem(t *testing.T) {
+	flightService := &StubFlightService{}
+	router := httpserver.NewRouter(handler.NewHealthHandler(), handler.NewFlightHandler(flightService), handler.NewAdminHandler("", nil))
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/flights", nil))
+
+	require.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+	require.Equal(t, http.MethodGet, recorder.Header().Get("Allow"))
+	require.Equal(t, problem.TypeMethodNotAllowed, decodeProblem(t, recorder)["type"])
+}
@@ -0,0 +1,107 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/config"
+	"github.com/Orden14/flight-aggregator/src/domain"
+	"github.com/Orden14/flight-aggregator/src/repository"
+	"github.com/Orden14/flight-aggregator/src/service"
+	"github.com/stretchr/testify/require"
+)
+
+func fetchProviderError(t *testing.T, handlerFunc http.HandlerFunc) *repository.ProviderError {
+	server := httptest.NewServer(handlerFunc)
+	t.Cleanup(server.Close)
+
+	providerConfig := serverConfig(t, server)
+	providerConfig.Timeout = 50 * time.Millisecond
+	providerConfig.Parsing = config.ParsingStrict
+
+	_, err := repository.NewServer1FlightRepository(providerConfig, testRetryPolicy(1)).Fetch(context.Background())
+
+	var providerError *repository.ProviderError
+	require.ErrorAs(t, err, &providerError)
+	require.Equal(t, providerConfig.Name, providerError.Provider)
+	require.Equal(t, server.URL+"/flights", providerError.URL)
+
+	return providerError
+}
+
+func TestProviderErrorCategories(t *testing.T) {
+	notFound := fetchProviderError(t, func(writer http.ResponseWriter, request *http.Request) {
+		http.NotFound(writer, request)
+	})
+	require.Equal(t, repository.ErrorCategoryBadStatus, notFound.Category)
+	require.Equal(t, http.StatusNotFound, notFound.StatusCode)
+	require.ErrorIs(t, notFound, repository.ErrBadStatus)
+	require.NotContains(t, notFound.Error(), notFound.URL)
+	require.NotContains(t, notFound.Error(), "not found")
+	require.Contains(t, notFound.Detail(), "404 page not found")
+
+	malformed := fetchProviderError(t, func(writer http.ResponseWriter, request *http.Request) {
+		_, _ = writer.Write([]byte(`{"not": "an array"}`))
+	})
+	require.Equal(t, repository.ErrorCategoryDecode, malformed.Category)
+	require.ErrorIs(t, malformed, repository.ErrDecode)
+	require.NotErrorIs(t, malformed, repository.ErrBadStatus)
+
+	badRecord := fetchProviderError(t, func(writer http.ResponseWriter, request *http.Request) {
+		_, _ = writer.Write([]byte(`[{"bookingId": "A1", "departureTime": "tomorrow"}]`))
+	})
+	require.Equal(t, repository.ErrorCategoryDecode, badRecord.Category)
+
+	slow := fetchProviderError(t, func(writer http.ResponseWriter, request *http.Request) {
+		select {
+		case <-request.Context().Done():
+		case <-time.After(time.Second):
+		}
+	})
+	require.Equal(t, repository.ErrorCategoryTimeout, slow.Category)
+	require.ErrorIs(t, slow, repository.ErrTimeout)
+	require.ErrorIs(t, slow, context.DeadlineExceeded)
+}
+
+func TestProviderErrorWithoutCategory(t *testing.T) {
+	require.Equal(t, "provider manual: provider error", (&repository.ProviderError{Provider: "manual"}).Error())
+	require.Equal(t, "provider manual: quota", (&repository.ProviderError{Provider: "manual", Category: "quota"}).Error())
+	require.NotErrorIs(t, &repository.ProviderError{Category: "quota"}, repository.ErrBadStatus)
+}
+
+func TestProviderErrorForUnreachableProvider(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	providerConfig := serverConfig(t, server)
+	server.Close()
+
+	_, err := repository.NewServer1FlightRepository(providerConfig, testRetryPolicy(1)).Fetch(context.Background())
+
+	var providerError *repository.ProviderError
+	require.ErrorAs(t, err, &providerError)
+	require.Equal(t, repository.ErrorCategoryTransport, providerError.Category)
+	require.ErrorIs(t, err, repository.ErrUnreachable)
+}
+
+func TestProviderStatusReportsErrorCategory(t *testing.T) {
+	flightService := service.NewFlightService(1,
+		&MockRepo{ProviderName: "ok"},
+		&MockRepo{ProviderName: "broken", FetchFunc: func(ctx context.Context) ([]domain.Flight, error) {
+			return nil, &repository.ProviderError{Provider: "broken", URL: "http://internal.example/flights", Body: "maintenance", Category: repository.ErrorCategoryBadStatus, StatusCode: http.StatusServiceUnavailable, Err: errors.New("secret")}
+		}},
+	)
+
+	result, err := flightService.GetFlights(context.Background(), service.FlightQuery{})
+	require.NoError(t, err)
+
+	for _, provider := range result.Providers {
+		if provider.Name == "broken" {
+			require.Equal(t, string(repository.ErrorCategoryBadStatus), provider.ErrorCategory)
+			require.Equal(t, http.StatusServiceUnavailable, provider.UpstreamStatus)
+			require.Equal(t, repository.ErrBadStatus.Error(), provider.Error)
+		}
+	}
+}
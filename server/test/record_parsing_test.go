@@ -0,0 +1,102 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/config"
+	"github.com/Orden14/flight-aggregator/src/repository"
+	"github.com/Orden14/flight-aggregator/src/service"
+	"github.com/stretchr/testify/require"
+)
+
+const server1PayloadWithBadRecord = `[
+	{"bookingId": "A1", "flightNumber": "JL046", "departureAirport": "CDG", "arrivalAirport": "HND", "departureTime": "2026-01-01T13:00:00Z", "arrivalTime": "2026-01-02T08:30:00Z", "price": 850.0, "currency": "EUR"},
+	{"bookingId": "A2", "flightNumber": "AF276", "departureAirport": "CDG", "arrivalAirport": "HND", "departureTime": "tomorrow", "arrivalTime": "2026-01-02T08:30:00Z", "price": 700.0, "currency": "EUR"}
+]`
+
+const server2PayloadWithBadRecord = `[
+	{"reference": "B1", "segments": [
+		{"flight": {"number": "AF276", "from": "CDG", "to": "HND", "depart": "2026-01-01T10:00:00Z", "arrive": "2026-01-01T23:00:00Z"}}
+	], "total": {"amount": 950.0, "currency": "EUR"}},
+	{"reference": "B2", "segments": [
+		{"flight": {"number": "AF276", "from": "CDG", "to": "FRA", "depart": "2026-01-01T10:00:00Z", "arrive": "2026-01-01T11:00:00Z"}},
+		{"flight": {"number": "LH716", "from": "FRA", "to": "HND", "depart": "soon", "arrive": "2026-01-02T08:00:00Z"}}
+	], "total": {"amount": 650.0, "currency": "EUR"}}
+]`
+
+func TestLenientParsingSkipsBadServer1Record(t *testing.T) {
+	flightRepository := repository.NewServer1FlightRepository(payloadConfig(t, server1PayloadWithBadRecord, config.ParsingLenient), testRetryPolicy(1))
+
+	report, err := flightRepository.FetchReport(context.Background())
+	require.NoError(t, err)
+	require.Len(t, report.Flights, 1)
+	require.Equal(t, "A1", report.Flights[0].Reference)
+	require.Equal(t, 1, report.SkippedRecords)
+	require.Len(t, report.Issues, 1)
+	require.Equal(t, "A2", report.Issues[0].Reference)
+	require.Equal(t, "departureTime", report.Issues[0].Field)
+	require.Equal(t, "missing or not an RFC 3339 date-time", report.Issues[0].Reason)
+}
+
+func TestStrictParsingFailsOnBadRecord(t *testing.T) {
+	flightRepository := repository.NewServer1FlightRepository(payloadConfig(t, server1PayloadWithBadRecord, config.ParsingStrict), testRetryPolicy(1))
+
+	_, err := flightRepository.Fetch(context.Background())
+	require.ErrorIs(t, err, repository.ErrDecode)
+
+	var providerError *repository.ProviderError
+	require.ErrorAs(t, err, &providerError)
+	require.Contains(t, providerError.Detail(), "A2")
+	require.NotContains(t, err.Error(), "A2")
+}
+
+func TestLenientParsingSkipsBadServer2Segment(t *testing.T) {
+	flightRepository := repository.NewServer2FlightRepository(payloadConfig(t, server2PayloadWithBadRecord, config.ParsingLenient), testRetryPolicy(1))
+
+	report, err := flightRepository.FetchReport(context.Background())
+	require.NoError(t, err)
+	require.Len(t, report.Flights, 1)
+	require.Equal(t, "B1", report.Flights[0].Reference)
+	require.Equal(t, []repository.RecordIssue{{Reference: "B2", Field: "segments[1].flight.depart", Reason: "missing or not an RFC 3339 date-time"}}, report.Issues)
+}
+
+func TestLenientParsingSkipsUnmappableJSONRecord(t *testing.T) {
+	providerConfig := payloadConfig(t, `[
+		{"bookingId": "C1", "flightNumber": "LH1", "departureAirport": "FRA", "arrivalAirport": "CDG", "departureTime": "2026-01-01T08:00:00Z", "arrivalTime": "2026-01-01T09:15:00Z", "price": 99, "currency": "EUR"},
+		{"bookingId": "C2", "flightNumber": "LH3", "departureAirport": "FRA", "arrivalAirport": "CDG", "departureTime": "2026-01-01T12:00:00Z", "arrivalTime": "2026-01-01T13:15:00Z", "currency": "EUR"}
+	]`, config.ParsingLenient)
+	providerConfig.Mapping = server1Mapping
+
+	flightRepository, err := repository.NewJSONFlightRepository(providerConfig, testRetryPolicy(1))
+	require.NoError(t, err)
+
+	report, err := flightRepository.FetchReport(context.Background())
+	require.NoError(t, err)
+	require.Len(t, report.Flights, 1)
+	require.Equal(t, 1, report.SkippedRecords)
+	require.Len(t, report.Issues, 1)
+	require.Equal(t, "C2", report.Issues[0].Reference)
+	require.Equal(t, "price", report.Issues[0].Field)
+	require.Equal(t, "missing or not a number", report.Issues[0].Reason)
+}
+
+func TestProviderStatusReportsSkippedRecords(t *testing.T) {
+	flightRepository := repository.NewCircuitBreakerRepository(
+		repository.NewServer1FlightRepository(payloadConfig(t, server1PayloadWithBadRecord, config.ParsingLenient), testRetryPolicy(1)),
+		config.CircuitBreakerConfig{FailureThreshold: 1},
+	)
+
+	flightService := service.NewFlightServiceWithOptions(service.FlightServiceOptions{CacheTTL: time.Minute}, flightRepository)
+
+	for range 2 {
+		result, err := flightService.GetFlights(context.Background(), service.FlightQuery{})
+		require.NoError(t, err)
+		require.Len(t, result.Flights, 1)
+		require.Equal(t, service.ProviderStatusOK, result.Providers[0].Status)
+		require.Equal(t, 1, result.Providers[0].SkippedRecords)
+		require.Len(t, result.Providers[0].RecordIssues, 1)
+		require.Equal(t, "A2", result.Providers[0].RecordIssues[0].Reference)
+	}
+}
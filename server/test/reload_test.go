@@ -0,0 +1,187 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/domain"
+	"github.com/Orden14/flight-aggregator/src/handler"
+	"github.com/Orden14/flight-aggregator/src/httpserver"
+	"github.com/Orden14/flight-aggregator/src/service"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReloadSwapsRepositoriesAndKeepsInflightSearches(t *testing.T) {
+	release := make(chan struct{})
+
+	oldRepo := &MockRepo{
+		ProviderName: "old",
+		FetchFunc: func(ctx context.Context) ([]domain.Flight, error) {
+			<-release
+
+			return []domain.Flight{{Reference: "OLD"}}, nil
+		},
+	}
+
+	newRepo := &MockRepo{
+		ProviderName: "new",
+		FetchFunc: func(ctx context.Context) ([]domain.Flight, error) {
+			return []domain.Flight{{Reference: "NEW"}}, nil
+		},
+	}
+
+	flightService := service.NewFlightServiceWithOptions(service.FlightServiceOptions{RepositoryTimeout: time.Second}, oldRepo)
+
+	inflightResult := make(chan *service.FlightSearchResult, 1)
+	inflightErr := make(chan error, 1)
+
+	go func() {
+		result, err := flightService.GetFlights(context.Background(), service.FlightQuery{})
+		inflightErr <- err
+		inflightResult <- result
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+
+	flightService.Reload(service.FlightServiceOptions{RepositoryTimeout: time.Second}, newRepo)
+
+	result, err := flightService.GetFlights(context.Background(), service.FlightQuery{})
+	require.NoError(t, err)
+	require.Equal(t, "NEW", result.Flights[0].Reference)
+	require.Equal(t, "new", result.Providers[0].Name)
+
+	close(release)
+
+	require.NoError(t, <-inflightErr)
+	result = <-inflightResult
+	require.Equal(t, "OLD", result.Flights[0].Reference)
+	require.Equal(t, "old", result.Providers[0].Name)
+}
+
+func TestReloadKeepsCachedDataOfRetainedProviders(t *testing.T) {
+	var calls int
+
+	flightRepository := &MockRepo{
+		ProviderName: "cached",
+		FetchFunc: func(ctx context.Context) ([]domain.Flight, error) {
+			calls++
+
+			return []domain.Flight{{Reference: "CACHED"}}, nil
+		},
+	}
+
+	options := service.FlightServiceOptions{RepositoryTimeout: time.Second, CacheTTL: time.Minute}
+	flightService := service.NewFlightServiceWithOptions(options, flightRepository)
+
+	_, err := flightService.GetFlights(context.Background(), service.FlightQuery{})
+	require.NoError(t, err)
+
+	options.CacheTTL = 2 * time.Minute
+	flightService.Reload(options, flightRepository)
+
+	result, err := flightService.GetFlights(context.Background(), service.FlightQuery{})
+	require.NoError(t, err)
+	require.Equal(t, string(service.CacheStatusHit), result.Providers[0].Cache)
+	require.Equal(t, 1, calls)
+}
+
+func TestReloadDropsCachedDataOfChangedProviders(t *testing.T) {
+	options := service.FlightServiceOptions{RepositoryTimeout: time.Second, CacheTTL: time.Minute}
+	flightService := service.NewFlightServiceWithOptions(options, fixedRepo("provider", domain.Flight{Reference: "OLD-URL"}))
+
+	_, err := flightService.GetFlights(context.Background(), service.FlightQuery{})
+	require.NoError(t, err)
+
+	flightService.Reload(options, fixedRepo("provider", domain.Flight{Reference: "NEW-URL"}))
+
+	result, err := flightService.GetFlights(context.Background(), service.FlightQuery{})
+	require.NoError(t, err)
+	require.Equal(t, "NEW-URL", result.Flights[0].Reference)
+	require.Equal(t, string(service.CacheStatusMiss), result.Providers[0].Cache)
+}
+
+func TestReloadDoesNotStoreFetchesOfRemovedProviders(t *testing.T) {
+	release := make(chan struct{})
+
+	oldRepo := &MockRepo{
+		ProviderName: "provider",
+		FetchFunc: func(ctx context.Context) ([]domain.Flight, error) {
+			<-release
+
+			return []domain.Flight{{Reference: "OLD"}}, nil
+		},
+	}
+
+	options := service.FlightServiceOptions{RepositoryTimeout: time.Second, CacheTTL: time.Minute}
+	flightService := service.NewFlightServiceWithOptions(options, oldRepo)
+
+	inflightErr := make(chan error, 1)
+
+	go func() {
+		_, err := flightService.GetFlights(context.Background(), service.FlightQuery{})
+		inflightErr <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+
+	flightService.Reload(options, fixedRepo("provider", domain.Flight{Reference: "NEW"}))
+	close(release)
+	require.NoError(t, <-inflightErr)
+
+	flightService.Reload(options, oldRepo)
+
+	result, err := flightService.GetFlights(context.Background(), service.FlightQuery{})
+	require.NoError(t, err)
+	require.Equal(t, string(service.CacheStatusMiss), result.Providers[0].Cache)
+}
+
+func TestAdminHandlerRecordsReloads(t *testing.T) {
+	adminHandler := handler.NewAdminHandler("config.yaml", []string{"j-server1"})
+
+	adminHandler.RecordReload(nil, errors.New("bad yaml"))
+
+	status := adminHandler.Status()
+	require.True(t, status.WatchEnabled)
+	require.Equal(t, handler.ReloadStatusError, status.LastReloadStatus)
+	require.Equal(t, "bad yaml", status.LastError)
+	require.Equal(t, []string{"j-server1"}, status.Providers)
+
+	adminHandler.RecordReload([]string{"j-server1", "j-server2"}, nil)
+
+	status = adminHandler.Status()
+	require.Equal(t, handler.ReloadStatusOK, status.LastReloadStatus)
+	require.Empty(t, status.LastError)
+	require.Equal(t, 2, status.ReloadCount)
+	require.Equal(t, 1, status.FailureCount)
+	require.Equal(t, []string{"j-server1", "j-server2"}, status.Providers)
+}
+
+func TestAdminConfigRequiresToken(t *testing.T) {
+	adminHandler := handler.NewAdminHandler("config.yaml", []string{"j-server1"})
+	router := httpserver.NewRouter(handler.NewHealthHandler(), handler.NewFlightHandler(&StubFlightService{}), adminHandler)
+
+	serveAdmin := func(authorization string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, "/admin/config", nil)
+
+		if authorization != "" {
+			request.Header.Set("Authorization", authorization)
+		}
+
+		return serveRequest(router, request)
+	}
+
+	require.Equal(t, http.StatusNotFound, serveAdmin("").Code)
+
+	adminHandler.SetToken("secret")
+
+	require.Equal(t, http.StatusUnauthorized, serveAdmin("").Code)
+	require.Equal(t, http.StatusUnauthorized, serveAdmin("Bearer wrong").Code)
+
+	recorder := serveAdmin("Bearer secret")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "j-server1")
+}
@@ -0,0 +1,138 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/config"
+	"github.com/Orden14/flight-aggregator/src/repository"
+	"github.com/stretchr/testify/require"
+)
+
+const server1Payload = `[{
+	"bookingId": "A10001",
+	"status": "confirmed",
+	"passengerName": "Marie Curie",
+	"flightNumber": "JL046",
+	"departureAirport": "CDG",
+	"arrivalAirport": "HND",
+	"departureTime": "2026-01-01T13:00:00Z",
+	"arrivalTime": "2026-01-02T08:30:00Z",
+	"price": 850.0,
+	"currency": "EUR"
+}]`
+
+func flakyServer(t *testing.T, failures int32, failureStatus int, retryAfter string) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if calls.Add(1) <= failures {
+			if retryAfter != "" {
+				writer.Header().Set("Retry-After", retryAfter)
+			}
+
+			writer.WriteHeader(failureStatus)
+
+			return
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		_, _ = writer.Write([]byte(server1Payload))
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server, &calls
+}
+
+func serverConfig(t *testing.T, server *httptest.Server) config.ProviderConfig {
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	return config.ProviderConfig{Name: serverURL.Host, BaseURL: server.URL}
+}
+
+func testRetryPolicy(maxAttempts int) repository.RetryPolicy {
+	return repository.NewRetryPolicy(config.RetryConfig{
+		MaxAttempts: maxAttempts,
+		BaseDelay:   5 * time.Millisecond,
+		MaxDelay:    20 * time.Millisecond,
+		Jitter:      0.5,
+	})
+}
+
+func TestRetrySucceedsAfterTransientFailures(t *testing.T) {
+	server, calls := flakyServer(t, 2, http.StatusServiceUnavailable, "")
+
+	flightRepository := repository.NewServer1FlightRepository(serverConfig(t, server), testRetryPolicy(3))
+
+	flights, err := flightRepository.Fetch(context.Background())
+	require.NoError(t, err)
+	require.Len(t, flights, 1)
+	require.Equal(t, int32(3), calls.Load())
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	server, calls := flakyServer(t, 5, http.StatusBadGateway, "")
+
+	flightRepository := repository.NewServer1FlightRepository(serverConfig(t, server), testRetryPolicy(3))
+
+	_, err := flightRepository.Fetch(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "502")
+	require.ErrorIs(t, err, repository.ErrBadStatus)
+	require.Equal(t, int32(3), calls.Load())
+}
+
+func TestRetrySkipsNonRetryableStatus(t *testing.T) {
+	server, calls := flakyServer(t, 1, http.StatusNotFound, "")
+
+	flightRepository := repository.NewServer1FlightRepository(serverConfig(t, server), testRetryPolicy(3))
+
+	_, err := flightRepository.Fetch(context.Background())
+	require.Error(t, err)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	server, calls := flakyServer(t, 1, http.StatusTooManyRequests, "0")
+
+	flightRepository := repository.NewServer1FlightRepository(serverConfig(t, server), testRetryPolicy(2))
+
+	flights, err := flightRepository.Fetch(context.Background())
+	require.NoError(t, err)
+	require.Len(t, flights, 1)
+	require.Equal(t, int32(2), calls.Load())
+}
+
+func TestRetryIgnoresTooManyRequestsWithoutRetryAfter(t *testing.T) {
+	server, calls := flakyServer(t, 1, http.StatusTooManyRequests, "")
+
+	flightRepository := repository.NewServer1FlightRepository(serverConfig(t, server), testRetryPolicy(3))
+
+	_, err := flightRepository.Fetch(context.Background())
+	require.ErrorIs(t, err, repository.ErrBadStatus)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestRetryRespectsContextDeadline(t *testing.T) {
+	server, calls := flakyServer(t, 5, http.StatusTooManyRequests, "10")
+
+	flightRepository := repository.NewServer1FlightRepository(serverConfig(t, server), testRetryPolicy(5))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := flightRepository.Fetch(ctx)
+	elapsed := time.Since(start)
+
+	require.Error(t, err)
+	require.Equal(t, int32(1), calls.Load())
+	require.Less(t, elapsed, time.Second)
+}
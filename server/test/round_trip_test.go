@@ -0,0 +1,145 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Orden14/flight-aggregator/src/domain"
+	"github.com/Orden14/flight-aggregator/src/service"
+	"github.com/Orden14/flight-aggregator/src/util/sorter"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripBody struct {
+	Total int                `json:"total"`
+	Items []domain.Itinerary `json:"items"`
+}
+
+func routeFlight(t *testing.T, reference string, from string, to string, departure string, arrival string, price float64) domain.Flight {
+	return domain.Flight{
+		Reference:     reference,
+		From:          from,
+		To:            to,
+		DepartureTime: tTime(t, departure),
+		ArrivalTime:   tTime(t, arrival),
+		Price:         price,
+		Currency:      "EUR",
+	}
+}
+
+func roundTripService(t *testing.T) service.FlightService {
+	return fixedService(
+		fixedRepo("outbound",
+			routeFlight(t, "O1", "CDG", "HND", "2026-01-01T10:00:00Z", "2026-01-01T23:00:00Z", 500),
+			routeFlight(t, "O2", "CDG", "HND", "2026-01-03T10:00:00Z", "2026-01-03T23:00:00Z", 300),
+		),
+		fixedRepo("return",
+			routeFlight(t, "R1", "HND", "CDG", "2026-01-05T10:00:00Z", "2026-01-05T23:00:00Z", 400),
+			routeFlight(t, "R2", "HND", "CDG", "2026-01-10T10:00:00Z", "2026-01-10T22:00:00Z", 200),
+			routeFlight(t, "R3", "HND", "ICN", "2026-01-05T10:00:00Z", "2026-01-05T12:00:00Z", 100),
+		),
+	)
+}
+
+func itineraryReferences(itineraries []domain.Itinerary) []string {
+	references := make([]string, 0, len(itineraries))
+
+	for _, itinerary := range itineraries {
+		references = append(references, itinerary.Reference)
+	}
+
+	return references
+}
+
+func TestRoundTripsPairFlightsAcrossProviders(t *testing.T) {
+	recorder, body := getJSON[roundTripBody](t, testRouter(roundTripService(t)), "/flights/round-trip?from=CDG&to=HND")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, []string{"O2+R2", "O1+R2", "O2+R1", "O1+R1"}, itineraryReferences(body.Items))
+
+	cheapest := body.Items[0]
+	require.Equal(t, 500.0, cheapest.Price)
+	require.Equal(t, "CDG", cheapest.From)
+	require.Equal(t, "CDG", cheapest.To)
+	require.Equal(t, 13*60+12*60, cheapest.TravelTimeMinutes)
+	require.Equal(t, (6*24+11)*60, cheapest.StayMinutes)
+	require.Len(t, cheapest.Flights, 2)
+}
+
+func TestRoundTripsApplyStayDateAndTotalPriceConstraints(t *testing.T) {
+	_, body := getJSON[roundTripBody](t, testRouter(roundTripService(t)), "/flights/round-trip?from=CDG&to=HND&min_stay=2&max_stay=7")
+	require.Equal(t, []string{"O2+R2", "O1+R1"}, itineraryReferences(body.Items))
+
+	_, body = getJSON[roundTripBody](t, testRouter(roundTripService(t)), "/flights/round-trip?from=CDG&to=HND&return_date=2026-01-05")
+	require.Equal(t, []string{"O2+R1", "O1+R1"}, itineraryReferences(body.Items))
+
+	_, body = getJSON[roundTripBody](t, testRouter(roundTripService(t)), "/flights/round-trip?from=CDG&to=HND&max_price=700")
+	require.Equal(t, []string{"O2+R2", "O1+R2", "O2+R1"}, itineraryReferences(body.Items))
+}
+
+func TestRoundTripsSortAndPaginate(t *testing.T) {
+	recorder, body := getJSON[roundTripBody](t, testRouter(roundTripService(t)), "/flights/round-trip?from=CDG&to=HND&sort=travel_time&order=asc&limit=2")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "4", recorder.Header().Get("X-Total-Count"))
+	require.Contains(t, recorder.Header().Get("Link"), "offset=2")
+	require.Equal(t, []string{"O1+R2", "O2+R2"}, itineraryReferences(body.Items))
+}
+
+func TestRoundTripsRejectInvalidStay(t *testing.T) {
+	recorder, _ := getJSON[roundTripBody](t, testRouter(roundTripService(t)), "/flights/round-trip?min_stay=5&max_stay=2&cursor=abc&interline=true")
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	for _, name := range []string{"min_stay", "cursor", "interline", "from", "to"} {
+		require.Contains(t, recorder.Body.String(), `"name":"`+name+`"`)
+	}
+}
+
+func TestRoundTripsRejectStaysAboveAYear(t *testing.T) {
+	for _, target := range []string{
+		"/flights/round-trip?from=CDG&to=HND&max_stay=999999999999",
+		"/flights/round-trip?from=CDG&to=HND&min_stay=366",
+		"/flights/round-trip?from=CDG&to=HND&max_stay=8785h",
+	} {
+		recorder, _ := getJSON[roundTripBody](t, testRouter(roundTripService(t)), target)
+		require.Equal(t, http.StatusBadRequest, recorder.Code, target)
+		require.Contains(t, recorder.Body.String(), "must not exceed 365 days", target)
+	}
+
+	recorder, _ := getJSON[roundTripBody](t, testRouter(roundTripService(t)), "/flights/round-trip?from=CDG&to=HND&max_stay=365")
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestRoundTripsEncodeEmptyItems(t *testing.T) {
+	recorder, _ := getJSON[roundTripBody](t, testRouter(roundTripService(t)), "/flights/round-trip?from=CDG&to=LAX")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `"items":[]`)
+}
+
+func TestRoundTripsKeepTheBestPairsOnly(t *testing.T) {
+	var flights []domain.Flight
+
+	for index := range 40 {
+		flights = append(flights,
+			routeFlight(t, fmt.Sprintf("O%d", index), "CDG", "HND", "2026-01-01T10:00:00Z", "2026-01-01T23:00:00Z", float64(100+index)),
+			routeFlight(t, fmt.Sprintf("R%d", index), "HND", "CDG", "2026-01-05T10:00:00Z", "2026-01-05T23:00:00Z", float64(100+index)),
+		)
+	}
+
+	flightService := service.NewFlightService(1, fixedRepo("provider", flights...))
+
+	recorder, body := getJSON[roundTripBody](t, testRouter(flightService), "/flights/round-trip?from=CDG&to=HND&limit=1")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "1000", recorder.Header().Get("X-Total-Count"))
+	require.Contains(t, recorder.Body.String(), `"truncated":true`)
+	require.Equal(t, []string{"O0+R0"}, itineraryReferences(body.Items))
+}
+
+func TestSortItinerariesByDeparture(t *testing.T) {
+	itineraries := []domain.Itinerary{
+		domain.NewItinerary(routeFlight(t, "B", "CDG", "HND", "2026-01-02T10:00:00Z", "2026-01-02T20:00:00Z", 100)),
+		domain.NewItinerary(routeFlight(t, "A", "CDG", "HND", "2026-01-01T10:00:00Z", "2026-01-01T20:00:00Z", 900)),
+	}
+
+	sorter.SortItineraries(itineraries, sorter.SortByDepartureDate, sorter.OrderAsc)
+	require.Equal(t, []string{"A", "B"}, itineraryReferences(itineraries))
+}
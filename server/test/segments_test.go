@@ -0,0 +1,78 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Orden14/flight-aggregator/src/domain"
+	"github.com/Orden14/flight-aggregator/src/repository"
+	"github.com/Orden14/flight-aggregator/src/service"
+	"github.com/stretchr/testify/require"
+)
+
+func findFlight(t *testing.T, flights []domain.Flight, reference string) domain.Flight {
+	for _, flight := range flights {
+		if flight.Reference == reference {
+			return flight
+		}
+	}
+
+	require.Failf(t, "flight not found", "reference %s", reference)
+
+	return domain.Flight{}
+}
+
+func TestServer2KeepsEverySegment(t *testing.T) {
+	server := jsonServerFromDB(t, "../../j-server2/db.json")
+
+	flights, err := repository.NewServer2FlightRepository(serverConfig(t, server), testRetryPolicy(1)).Fetch(context.Background())
+	require.NoError(t, err)
+
+	flight := findFlight(t, flights, "B30004")
+	require.Len(t, flight.Segments, 2)
+	require.Equal(t, "KE902", flight.Segments[0].FlightNumber)
+	require.Equal(t, "ICN", flight.Segments[0].To)
+	require.Equal(t, "KE711", flight.Segments[1].FlightNumber)
+	require.Equal(t, "CDG", flight.From)
+	require.Equal(t, "HND", flight.To)
+}
+
+func TestServer1FlightsAreSingleSegment(t *testing.T) {
+	server := jsonServerFromDB(t, "../../j-server1/db.json")
+
+	flights, err := repository.NewServer1FlightRepository(serverConfig(t, server), testRetryPolicy(1)).Fetch(context.Background())
+	require.NoError(t, err)
+
+	for _, flight := range flights {
+		require.Len(t, flight.Segments, 1)
+		require.Equal(t, flight.FlightNumber, flight.Segments[0].FlightNumber)
+		require.Equal(t, flight.DepartureTime, flight.Segments[0].DepartureTime)
+	}
+}
+
+func TestStopsAndLayoversAreDerived(t *testing.T) {
+	flightRepository := &MockRepo{
+		ProviderName: "connecting",
+		FetchFunc: func(ctx context.Context) ([]domain.Flight, error) {
+			return []domain.Flight{
+				{
+					Reference:     "CONN",
+					From:          "CDG",
+					To:            "HND",
+					DepartureTime: tTime(t, "2026-01-01T09:30:00Z"),
+					ArrivalTime:   tTime(t, "2026-01-02T00:30:00Z"),
+					Segments: []domain.Segment{
+						{FlightNumber: "KE902", From: "CDG", To: "ICN", DepartureTime: tTime(t, "2026-01-01T09:30:00Z"), ArrivalTime: tTime(t, "2026-01-01T18:00:00Z")},
+						{FlightNumber: "KE711", From: "ICN", To: "HND", DepartureTime: tTime(t, "2026-01-01T20:00:00Z"), ArrivalTime: tTime(t, "2026-01-02T00:30:00Z")},
+					},
+				},
+			}, nil
+		},
+	}
+
+	result, err := service.NewFlightService(1, flightRepository).GetFlights(context.Background(), service.FlightQuery{})
+	require.NoError(t, err)
+	require.Len(t, result.Flights, 1)
+	require.Equal(t, 1, result.Flights[0].Stops)
+	require.Equal(t, []domain.Layover{{Airport: "ICN", DurationMinutes: 120}}, result.Flights[0].Layovers)
+}
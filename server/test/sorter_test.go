@@ -65,3 +65,21 @@ func TestSortByTravelTimeAsc(t *testing.T) {
 	require.Equal(t, "R1", flights[1].Reference)
 	require.Equal(t, "R3", flights[2].Reference)
 }
+
+func TestSortBreaksTiesByReference(t *testing.T) {
+	flights := []domain.Flight{
+		{Reference: "T2", Price: 500},
+		{Reference: "T3", Price: 400},
+		{Reference: "T1", Price: 500},
+	}
+
+	sorter.SortFlights(flights, sorter.SortByPrice, sorter.OrderAsc)
+	require.Equal(t, "T3", flights[0].Reference)
+	require.Equal(t, "T1", flights[1].Reference)
+	require.Equal(t, "T2", flights[2].Reference)
+
+	sorter.SortFlights(flights, sorter.SortByPrice, sorter.OrderDesc)
+	require.Equal(t, "T2", flights[0].Reference)
+	require.Equal(t, "T1", flights[1].Reference)
+	require.Equal(t, "T3", flights[2].Reference)
+}
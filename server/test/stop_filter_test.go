@@ -0,0 +1,85 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Orden14/flight-aggregator/src/domain"
+	"github.com/Orden14/flight-aggregator/src/service"
+	"github.com/stretchr/testify/require"
+)
+
+func connectingFlight(t *testing.T, reference string, layoverAirports []string, layoverDurations []time.Duration) domain.Flight {
+	departureTime := tTime(t, "2026-01-01T08:00:00Z")
+	airports := append(append([]string{"CDG"}, layoverAirports...), "HND")
+
+	var segments []domain.Segment
+
+	currentTime := departureTime
+
+	for index := 0; index < len(airports)-1; index++ {
+		if index > 0 {
+			currentTime = currentTime.Add(layoverDurations[index-1])
+		}
+
+		segments = append(segments, domain.Segment{
+			FlightNumber:  reference + "-" + airports[index],
+			From:          airports[index],
+			To:            airports[index+1],
+			DepartureTime: currentTime,
+			ArrivalTime:   currentTime.Add(5 * time.Hour),
+		})
+
+		currentTime = currentTime.Add(5 * time.Hour)
+	}
+
+	return domain.Flight{
+		Reference:     reference,
+		From:          "CDG",
+		To:            "HND",
+		Price:         500,
+		DepartureTime: departureTime,
+		ArrivalTime:   currentTime,
+		Segments:      segments,
+	}
+}
+
+func stopFilterService(t *testing.T) service.FlightService {
+	return fixedService(fixedRepo("itineraries",
+		connectingFlight(t, "DIRECT", nil, nil),
+		connectingFlight(t, "TIGHT", []string{"ICN"}, []time.Duration{40 * time.Minute}),
+		connectingFlight(t, "COMFY", []string{"HEL"}, []time.Duration{2 * time.Hour}),
+		connectingFlight(t, "OVERNIGHT", []string{"DXB"}, []time.Duration{11 * time.Hour}),
+		connectingFlight(t, "TWO-STOPS", []string{"AMS", "HKG"}, []time.Duration{2 * time.Hour, 3 * time.Hour}),
+	))
+}
+
+func searchReferences(t *testing.T, flightService service.FlightService, query service.FlightQuery) []string {
+	result, err := flightService.GetFlights(context.Background(), query)
+	require.NoError(t, err)
+
+	return flightReferences(result.Flights)
+}
+
+func TestFilterDirectOnly(t *testing.T) {
+	references := searchReferences(t, stopFilterService(t), service.FlightQuery{DirectOnly: true})
+
+	require.ElementsMatch(t, []string{"DIRECT"}, references)
+}
+
+func TestFilterMaxStops(t *testing.T) {
+	maxStops := 1
+	references := searchReferences(t, stopFilterService(t), service.FlightQuery{MaxStops: &maxStops})
+
+	require.ElementsMatch(t, []string{"DIRECT", "TIGHT", "COMFY", "OVERNIGHT"}, references)
+}
+
+func TestFilterLayoverWindow(t *testing.T) {
+	references := searchReferences(t, stopFilterService(t), service.FlightQuery{
+		MinLayover: time.Hour,
+		MaxLayover: 4 * time.Hour,
+	})
+
+	require.ElementsMatch(t, []string{"DIRECT", "COMFY", "TWO-STOPS"}, references)
+}